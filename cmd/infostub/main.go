@@ -0,0 +1,57 @@
+// Command infostub serves the external song details API (GET /info?group=&song=) from fixture files
+// for local development:
+//
+//	go run ./cmd/infostub -addr :8081 -fixtures ./cmd/infostub/fixtures -latency 200ms -error-rate 0.2 -error-status 500,503,429
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/infostub"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+func main() {
+	ctx := context.Background()
+	addr := flag.String("addr", ":8081", "address to listen on")
+	fixturesDir := flag.String("fixtures", "./cmd/infostub/fixtures", "directory with .json fixtures")
+	latency := flag.Duration("latency", 0, "delay of every answer")
+	jitter := flag.Duration("jitter", 0, "random delay added to the latency")
+	errorRate := flag.Float64("error-rate", 0, "share of requests failing with an error status, from 0 to 1")
+	errorStatuses := flag.String("error-status", "500", "comma separated statuses of failed requests")
+	flag.Parse()
+
+	if *errorRate < 0 || *errorRate > 1 {
+		logger.Log().Fatal(ctx, "error-rate must be between 0 and 1")
+	}
+	var statuses []int
+	for _, value := range strings.Split(*errorStatuses, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || status < 100 || status > 599 {
+			logger.Log().Fatal(ctx, "invalid error status %q", value)
+		}
+		statuses = append(statuses, status)
+	}
+	fixtures, err := infostub.LoadFixtures(*fixturesDir)
+	if err != nil {
+		logger.Log().Fatal(ctx, "error with fixtures: %s", err.Error())
+	}
+	stub := infostub.New(fixtures,
+		infostub.Latency(*latency, *jitter),
+		infostub.ErrorRate(*errorRate, statuses...),
+	)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           stub.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	logger.Log().Info(ctx, "info stub with %d fixtures was started on %s", len(fixtures), *addr)
+	if err := server.ListenAndServe(); err != nil {
+		logger.Log().Fatal(ctx, "HTTP server error: %v", err)
+	}
+}
@@ -2,7 +2,11 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kleo-53/music-system/pkg/logger"
@@ -12,6 +16,49 @@ type Config struct {
 	Port     string
 	DBURL    string
 	LogLevel string
+
+	// FuzzyThreshold is the default trigram similarity for match=fuzzy, 0 means the service default.
+	FuzzyThreshold float64
+	// TrashRetention is how long deleted songs stay in the trash, 0 means the service default.
+	TrashRetention time.Duration
+	// ImportBatchSize is how many songs a bulk import inserts with one statement, 0 means the service default.
+	ImportBatchSize int
+	// AdminToken protects admin endpoints, they are disabled when it is empty.
+	AdminToken string
+
+	// EnrichURL is the song details API, details are not requested when it is empty.
+	EnrichURL     string
+	EnrichTimeout time.Duration
+	// EnrichRetries is the number of retries of failed requests, -1 means the client default.
+	EnrichRetries int
+	// EnrichWorkers and EnrichMaxAttempts configure the background enrichment, 0 means the default.
+	EnrichWorkers     int
+	EnrichMaxAttempts int
+	// EnrichRefresh is how often songs still missing details are queued again, 0 disables it and -1 means the default.
+	EnrichRefresh time.Duration
+	// DetailsProviders are the sources of song details in the default order. Without them
+	// EnrichURL is the only provider, named "api".
+	DetailsProviders []DetailsProvider
+	// DetailsPrecedence lists for a detail (text, releaseDate, link) the providers asked for it in order.
+	DetailsPrecedence map[string][]string
+	// DetailsCache is off, memory or postgres, memory by default. Size and TTLs of 0 mean the cache defaults.
+	DetailsCache            string
+	DetailsCacheSize        int
+	DetailsCacheTTL         time.Duration
+	DetailsCacheNegativeTTL time.Duration
+}
+
+const (
+	DetailsCacheOff      = "off"
+	DetailsCacheMemory   = "memory"
+	DetailsCachePostgres = "postgres"
+)
+
+// DetailsProvider is a named source of song details, Source is the URL of an API with the /info contract
+// or the path to a .json or .csv reference file.
+type DetailsProvider struct {
+	Name   string
+	Source string
 }
 
 func NewConfig() (*Config, error) {
@@ -19,9 +66,126 @@ func NewConfig() (*Config, error) {
 		logger.Log().Warn(context.Background(), "No .env file found, using environment variables")
 	}
 	cfg := &Config{
-		Port:     os.Getenv("HOST_PORT"),
-		DBURL:    os.Getenv("DB_URL"),
-		LogLevel: os.Getenv("LOG_LEVEL"),
+		Port:       os.Getenv("HOST_PORT"),
+		DBURL:      os.Getenv("DB_URL"),
+		LogLevel:   os.Getenv("LOG_LEVEL"),
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+		EnrichURL:  os.Getenv("EXTERNAL_API_URL"),
+
+		DetailsCache: os.Getenv("DETAILS_CACHE"),
+	}
+	cfg.EnrichRetries = -1
+	cfg.EnrichRefresh = -1
+	if threshold := os.Getenv("FUZZY_THRESHOLD"); threshold != "" {
+		value, err := strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FUZZY_THRESHOLD: %w", err)
+		}
+		cfg.FuzzyThreshold = value
+	}
+	if retention := os.Getenv("TRASH_RETENTION"); retention != "" {
+		value, err := time.ParseDuration(retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRASH_RETENTION: %w", err)
+		}
+		cfg.TrashRetention = value
+	}
+	if size := os.Getenv("IMPORT_BATCH_SIZE"); size != "" {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("invalid IMPORT_BATCH_SIZE: %q", size)
+		}
+		cfg.ImportBatchSize = value
+	}
+	if timeout := os.Getenv("EXTERNAL_API_TIMEOUT"); timeout != "" {
+		value, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXTERNAL_API_TIMEOUT: %w", err)
+		}
+		cfg.EnrichTimeout = value
+	}
+	if retries := os.Getenv("EXTERNAL_API_RETRIES"); retries != "" {
+		value, err := strconv.Atoi(retries)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid EXTERNAL_API_RETRIES: %q", retries)
+		}
+		cfg.EnrichRetries = value
+	}
+	if workers := os.Getenv("ENRICH_WORKERS"); workers != "" {
+		value, err := strconv.Atoi(workers)
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("invalid ENRICH_WORKERS: %q", workers)
+		}
+		cfg.EnrichWorkers = value
+	}
+	if attempts := os.Getenv("ENRICH_MAX_ATTEMPTS"); attempts != "" {
+		value, err := strconv.Atoi(attempts)
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("invalid ENRICH_MAX_ATTEMPTS: %q", attempts)
+		}
+		cfg.EnrichMaxAttempts = value
+	}
+	if refresh := os.Getenv("ENRICH_REFRESH_INTERVAL"); refresh != "" {
+		value, err := time.ParseDuration(refresh)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid ENRICH_REFRESH_INTERVAL: %q", refresh)
+		}
+		cfg.EnrichRefresh = value
+	}
+	if providers := os.Getenv("DETAILS_PROVIDERS"); providers != "" {
+		// lyrics=http://lyrics/info,dates=./dates.csv
+		for _, provider := range strings.Split(providers, ",") {
+			name, source, ok := strings.Cut(provider, "=")
+			name, source = strings.TrimSpace(name), strings.TrimSpace(source)
+			if !ok || name == "" || source == "" {
+				return nil, fmt.Errorf("invalid DETAILS_PROVIDERS: %q, expected name=source", provider)
+			}
+			cfg.DetailsProviders = append(cfg.DetailsProviders, DetailsProvider{Name: name, Source: source})
+		}
+	}
+	if precedence := os.Getenv("DETAILS_PRECEDENCE"); precedence != "" {
+		// text=lyrics,api;releaseDate=dates
+		cfg.DetailsPrecedence = map[string][]string{}
+		for _, rule := range strings.Split(precedence, ";") {
+			detail, providers, ok := strings.Cut(rule, "=")
+			detail = strings.TrimSpace(detail)
+			if !ok || detail == "" {
+				return nil, fmt.Errorf("invalid DETAILS_PRECEDENCE: %q, expected detail=provider,provider", rule)
+			}
+			for _, provider := range strings.Split(providers, ",") {
+				if provider = strings.TrimSpace(provider); provider != "" {
+					cfg.DetailsPrecedence[detail] = append(cfg.DetailsPrecedence[detail], provider)
+				}
+			}
+		}
+	}
+	switch cfg.DetailsCache {
+	case "":
+		cfg.DetailsCache = DetailsCacheMemory
+	case DetailsCacheOff, DetailsCacheMemory, DetailsCachePostgres:
+	default:
+		return nil, fmt.Errorf("invalid DETAILS_CACHE: %q, expected off, memory or postgres", cfg.DetailsCache)
+	}
+	if size := os.Getenv("DETAILS_CACHE_SIZE"); size != "" {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("invalid DETAILS_CACHE_SIZE: %q", size)
+		}
+		cfg.DetailsCacheSize = value
+	}
+	if ttl := os.Getenv("DETAILS_CACHE_TTL"); ttl != "" {
+		value, err := time.ParseDuration(ttl)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid DETAILS_CACHE_TTL: %q", ttl)
+		}
+		cfg.DetailsCacheTTL = value
+	}
+	if ttl := os.Getenv("DETAILS_CACHE_NEGATIVE_TTL"); ttl != "" {
+		value, err := time.ParseDuration(ttl)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid DETAILS_CACHE_NEGATIVE_TTL: %q", ttl)
+		}
+		cfg.DetailsCacheNegativeTTL = value
 	}
 	return cfg, nil
 }
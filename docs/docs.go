@@ -19,9 +19,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/songs": {
+        "/api/v1/albums": {
             "get": {
-                "description": "Get info about all songs with pagination and optional filters",
+                "description": "Get all albums with their tracklists, pagination and optional filters",
                 "consumes": [
                     "application/json"
                 ],
@@ -29,38 +29,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Get songs info",
+                "summary": "Get albums",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by group name",
-                        "name": "group",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by song name",
-                        "name": "song",
+                        "type": "integer",
+                        "description": "Filter by group ID",
+                        "name": "group_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by text content",
-                        "name": "text",
+                        "description": "Filter by group name",
+                        "name": "group",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by release date",
-                        "name": "release_date",
+                        "description": "Filter by album title",
+                        "name": "title",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by link",
-                        "name": "link",
+                        "type": "integer",
+                        "description": "Filter by song on the album",
+                        "name": "song_id",
                         "in": "query"
                     },
                     {
@@ -73,7 +67,7 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 10,
-                        "description": "Number of songs per page",
+                        "description": "Number of albums per page, at most 100",
                         "name": "page_size",
                         "in": "query"
                     }
@@ -84,32 +78,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongCommon"
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Album"
                             }
                         }
                     },
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     },
                     "500": {
-                        "description": "Failed to get any songs data",
+                        "description": "Failed to get any albums data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Add a new song to the system",
+                "description": "Add a new album; the group is found by name or created",
                 "consumes": [
                     "application/json"
                 ],
@@ -117,138 +105,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Add song",
+                "summary": "Add album",
                 "parameters": [
                     {
-                        "description": "New song data",
+                        "description": "New album data",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongCommon"
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.AlbumCommon"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Song was added",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Album"
                         }
                     },
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     },
                     "500": {
-                        "description": "Failed to add song",
+                        "description": "Failed to add album",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/api/v1/songs/{song_id}": {
+        "/api/v1/albums/{album_id}": {
             "get": {
-                "description": "Get text of song by ID with pagination",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get album with its tracklist by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Get song text",
+                "summary": "Get album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "song_id",
+                        "description": "Album ID",
+                        "name": "album_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of verses per page",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Album"
                         }
                     },
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     },
                     "500": {
-                        "description": "Failed to get song text",
+                        "description": "Failed to get album",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a song by ID",
+                "description": "Delete an album by ID, its songs are kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Delete song",
+                "summary": "Delete album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "song_id",
+                        "description": "Album ID",
+                        "name": "album_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Song was deleted",
+                        "description": "Album was deleted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -259,25 +224,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete song",
+                        "description": "Failed to delete album",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     }
                 }
             },
             "patch": {
-                "description": "Update song information by ID",
+                "description": "Update album information by ID as a JSON Merge Patch: absent fields are kept, null clears the release date",
                 "consumes": [
                     "application/json"
                 ],
@@ -285,30 +250,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Update song",
+                "summary": "Update album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "song_id",
+                        "description": "Album ID",
+                        "name": "album_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated song data",
+                        "description": "Fields to change",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongFilters"
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.AlbumPatch"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Song was updated",
+                        "description": "Album was updated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -319,55 +284,2613 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
                         }
                     },
                     "500": {
-                        "description": "Failed to update song info",
+                        "description": "Failed to update album",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/albums/{album_id}/tracks": {
+            "put": {
+                "description": "Replace the tracklist of the album with existing songs in the given order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Set album tracks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "album_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Song IDs in track order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.AlbumTracks"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Album tracks were updated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Album not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid or unknown songs",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update album tracks",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_kleo-53_music-system_internal_controller_model.SongCommon": {
-            "description": "Minimal required data to represent a song",
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string"
-                },
-                "song": {
-                    "type": "string"
-                }
-            }
         },
-        "github_com_kleo-53_music-system_internal_controller_model.SongFilters": {
-            "description": "Used for filtering songs by fields below",
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string"
-                },
-                "link": {
-                    "type": "string"
-                },
-                "release_date": {
-                    "type": "string"
-                },
-                "song": {
-                    "type": "string"
-                },
-                "text": {
+        "/api/v1/groups": {
+            "get": {
+                "description": "Get all groups with pagination and optional filter by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get groups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by group name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of groups per page, at most 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Group"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get any groups data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new group to the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Add group",
+                "parameters": [
+                    {
+                        "description": "New group data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.GroupCommon"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add group",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/groups/{group_id}": {
+            "get": {
+                "description": "Get group by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get group",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a group by ID. Groups that still have songs cannot be deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group was deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Group still has songs or albums",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete group",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Rename group by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Update group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated group data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.GroupCommon"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group was updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Group already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update group",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "description": "Full-text search over song titles, group names and lyrics ordered by relevance.\nWords are combined with AND, \"quoted words\" match a phrase, word* matches a prefix and -word excludes a word.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Search songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of songs per page, at most 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Search query has no words",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to search songs",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs": {
+            "get": {
+                "description": "Get info about all songs with pagination and optional filters.\nCursor pagination is stable when songs are added or deleted between requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get songs info",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by group ID",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by song name",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by words in the text",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by release date",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by link",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by album ID, songs are returned in track order",
+                        "name": "album_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "fuzzy"
+                        ],
+                        "type": "string",
+                        "default": "exact",
+                        "description": "How group and song filters match: exact substring or fuzzy similarity ordered by score",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal similarity for fuzzy match, from 0 to 1",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort keys: group, song, release_date, id; prefix with - or add :desc for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, cannot be used with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Switches to cursor pagination: empty for the first page, then next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of songs per page, at most 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongPage"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of songs matching the filters"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid match mode, threshold, sort key or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get any songs data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new song to the system. The song is returned at once with the pending enrichment status,\nits details are requested from the external API in the background",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Add song",
+                "parameters": [
+                    {
+                        "description": "New song data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongCommon"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add song",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/export": {
+            "get": {
+                "description": "Download all songs matching the filters of the song listing without pagination.\nSongs are streamed from the database, so the export of the whole library does not need much memory",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Export songs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by group ID",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by group name",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by song name",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by words in the text",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by release date",
+                        "name": "release_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by link",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by album ID, songs are returned in track order",
+                        "name": "album_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "fuzzy"
+                        ],
+                        "type": "string",
+                        "default": "exact",
+                        "description": "How group and song filters match",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal similarity for fuzzy match, from 0 to 1",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sort keys: group, song, release_date, id; prefix with - or add :desc for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid match mode, threshold or sort key",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export songs",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/trash": {
+            "get": {
+                "description": "Get deleted songs, most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of songs per page, at most 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongPage"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of songs in the trash"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get trash",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete songs that were in the trash longer than the configured retention",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Purge trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of purged songs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to purge trash",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}": {
+            "get": {
+                "description": "Get full song data by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the song",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the song"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The song was not changed"
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get song",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Move a song to the trash by ID, it can be restored until the trash is purged",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the song must still have",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Song was deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete song",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update song information by ID with a JSON Merge Patch (RFC 7396): absent fields are kept,\nnull clears text, releaseDate or link. All changes are applied atomically",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Update song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the song must still have",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Changed song fields",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Song was changed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update song info",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/enrich": {
+            "post": {
+                "description": "Request the details of the song from the external API again and save them. By default only empty details are filled, overwrite=true replaces the filled ones too.\nA song the external API does not know keeps its details and gets the enrichment status not_found",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Enrich song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Replace details that are already filled",
+                        "name": "overwrite",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip the details cache, requires X-Admin-Token",
+                        "name": "X-Cache-Bypass",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to enrich song",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "External API is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "External API is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/history": {
+            "get": {
+                "description": "Get revisions of the song, newest first. The history is kept for deleted and purged songs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of revisions per page, at most 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get song history",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/lyrics": {
+            "get": {
+                "description": "Get the text of the song split into sections (intro, verse, chorus, bridge, outro) with repeats. Markers like [Chorus] or x2 are recognized, unmarked stanzas sung more than once are choruses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Drop the lines of repeated sections, they keep repeatOf",
+                        "name": "collapse",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Lyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get song lyrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/lyrics.lrc": {
+            "put": {
+                "description": "Replace the time-synced lyrics of the song with LRC, enhanced LRC with word timestamps like \u003c00:12.50\u003e is supported",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Upload synced lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "LRC file",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "LRC is larger than 1 MB",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid LRC",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to upload synced lyrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/lyrics.{format}": {
+            "get": {
+                "description": "Get the synced lyrics of the song as LRC (as uploaded), WebVTT or SubRip subtitles",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Export synced lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "lrc",
+                            "vtt",
+                            "srt"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or its synced lyrics not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export synced lyrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/lyrics/active": {
+            "get": {
+                "description": "Get the line of the synced lyrics sung at the playback offset, the word too for enhanced LRC",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get active line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playback offset: seconds (83.5), a duration (1m23.5s) or mm:ss.xx (01:23.50)",
+                        "name": "at",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.ActiveLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or its synced lyrics not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Negative offset",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get active line",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/lyrics/synced": {
+            "get": {
+                "description": "Get the time-synced lyrics of the song as lines with their times",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get synced lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or its synced lyrics not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get synced lyrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/restore": {
+            "post": {
+                "description": "Restore a deleted song from the trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Restore song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song is not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to restore song",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/revert/{revision}": {
+            "post": {
+                "description": "Return the song to its state right after the given revision. The revert is recorded as a new revision",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Revert song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who makes the change",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Revision cannot be reverted to",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revert song",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/text": {
+            "get": {
+                "description": "Get text of song by ID with pagination by verses, lines or a character budget.\nVerses are separated by blank lines, Windows line endings included.\nBreaking change: the endpoint used to return a plain array of verses with 201 Created, it now returns a page object with 200 OK",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song text",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "verse",
+                            "line",
+                            "chars"
+                        ],
+                        "type": "string",
+                        "default": "verse",
+                        "description": "Pagination unit",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of verses or lines per page, at most 100 (default 10), or of characters for unit=chars, at most 10000 (default 1000)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 639 code of the translation, the original text by default",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.TextPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or its text in the language not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown unit",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get song text",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/translations": {
+            "get": {
+                "description": "Get the original text of the song and its translations, the original goes first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "List song translations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.LyricsVersion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list song translations",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add or replace the text of the song in a language. The language is detected from the text when not given.\nWith original the version becomes the original text of the song, the previous original is kept as a translation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Add song translation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.LyricsVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.LyricsVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The language is the original language of the song or the previous original cannot be kept",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid language or the language cannot be detected",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add song translation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs/{song_id}/translations/{lang}": {
+            "get": {
+                "description": "Get the text of the song in a language, the original one included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song translation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 639 code of the language",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.LyricsVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Song or its text in the language not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get song translation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs:batch": {
+            "post": {
+                "description": "Create many songs at once from a JSON array or NDJSON (one song per line). Songs are inserted in batches\nand every item gets its own result, so invalid items do not stop the import. Missing details are requested\nby the enrichment workers after the import (defer, the default), from the external API before inserting (sync)\nor not at all (none). With sync the request waits for the external API, which can take long for big imports",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Import songs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "defer",
+                            "sync",
+                            "none"
+                        ],
+                        "type": "string",
+                        "default": "defer",
+                        "description": "When to request missing details",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Songs to import, at most 10000",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongImport"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip the details cache with enrich=sync, requires X-Admin-Token. The enrichment workers always use the cache",
+                        "name": "X-Cache-Bypass",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request is larger than 64 MB",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No songs or too many songs",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to import songs",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/songs:enrich": {
+            "post": {
+                "description": "Queue every song missing any of the given details for the background enrichment workers. Songs that already wait for enrichment are skipped, songs with the enrichment status skipped or not_found are queued too.\nRequires the admin token, with overwrite the details edited by users are replaced across the library",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Enrich songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated details: text, releaseDate, link; all by default",
+                        "name": "missing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Replace details that are already filled",
+                        "name": "overwrite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.EnrichmentQueued"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown detail",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to queue songs",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "External API is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_kleo-53_music-system_internal_controller_model.ActiveLine": {
+            "description": "The line active at the requested offset",
+            "type": "object",
+            "properties": {
+                "atMs": {
+                    "type": "integer"
+                },
+                "ended": {
+                    "type": "boolean"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "line": {
+                    "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedLine"
+                },
+                "next": {
+                    "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedLine"
+                },
+                "word": {
+                    "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedWord"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.Album": {
+            "description": "Represents a music album entity",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "groupId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.AlbumTrack"
+                    }
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.AlbumCommon": {
+            "description": "Data of an album without tracklist",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.AlbumPatch": {
+            "description": "Fields to change, absent fields are kept and null clears the field",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.AlbumTrack": {
+            "description": "Song placed on the album",
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.AlbumTracks": {
+            "description": "Song IDs in track order",
+            "type": "object",
+            "properties": {
+                "songIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.EnrichmentQueued": {
+            "description": "Number of songs queued for enrichment",
+            "type": "object",
+            "properties": {
+                "queued": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.Group": {
+            "description": "Represents a music group entity",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.GroupCommon": {
+            "description": "Minimal required data to represent a group",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.Lyrics": {
+            "description": "Structured lyrics of a song",
+            "type": "object",
+            "properties": {
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.LyricsSection"
+                    }
+                },
+                "songId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.LyricsSection": {
+            "description": "A verse, chorus, bridge, intro or outro",
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "repeat": {
+                    "type": "integer"
+                },
+                "repeatOf": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.LyricsVersion": {
+            "description": "The original text of the song or its translation",
+            "type": "object",
+            "properties": {
+                "detected": {
+                    "type": "boolean"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "original": {
+                    "type": "boolean"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.LyricsVersionRequest": {
+            "description": "A translation or the language of the original text",
+            "type": "object",
+            "properties": {
+                "lang": {
+                    "type": "string"
+                },
+                "original": {
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.Problem": {
+            "description": "Problem details of a failed request",
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.Song": {
+            "description": "Represents a music song entity",
+            "type": "object",
+            "properties": {
+                "deletedAt": {
+                    "type": "string"
+                },
+                "enrichmentStatus": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "groupId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongCommon": {
+            "description": "Minimal required data to represent a song",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongImport": {
+            "description": "Song with optional details, missing details can be requested from the external API",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongImportReport": {
+            "description": "Counts of created and failed songs with a result for every item",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongImportResult"
+                    }
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongImportResult": {
+            "description": "Result for the item with the same index in the request",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongPage": {
+            "description": "Songs of one page with the total number of matching songs",
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.Song"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongPatch": {
+            "description": "Fields to change, absent fields are kept and null clears the field",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongRevision": {
+            "description": "Immutable record of a song change",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "new": {
+                    "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongSnapshot"
+                },
+                "old": {
+                    "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SongSnapshot"
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongSearchResult": {
+            "description": "Song matching the search query",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "groupId": {
+                    "type": "integer"
+                },
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "releaseDate": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SongSnapshot": {
+            "description": "Song fields at the moment of a revision",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "groupId": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "sources": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SyncedLine": {
+            "description": "A line with the time it is sung",
+            "type": "object",
+            "properties": {
+                "endMs": {
+                    "type": "integer"
+                },
+                "startMs": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "words": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedWord"
+                    }
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SyncedLyrics": {
+            "description": "Lyrics with line timestamps, the LRC offset is already applied",
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artist": {
+                    "type": "string"
+                },
+                "lengthMs": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_kleo-53_music-system_internal_controller_model.SyncedLine"
+                    }
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.SyncedWord": {
+            "description": "A word with the time it starts",
+            "type": "object",
+            "properties": {
+                "startMs": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_kleo-53_music-system_internal_controller_model.TextPage": {
+            "description": "Verses, lines or lines fitting a character budget of one page with the size of the whole text",
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_lines": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "total_verses": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
                 }
             }
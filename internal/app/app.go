@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -14,7 +15,13 @@ import (
 	"github.com/kleo-53/music-system/config"
 	v1 "github.com/kleo-53/music-system/internal/controller"
 	"github.com/kleo-53/music-system/internal/migrate"
+	albumService "github.com/kleo-53/music-system/internal/service/album"
+	"github.com/kleo-53/music-system/internal/service/enrichment"
+	groupService "github.com/kleo-53/music-system/internal/service/group"
 	songService "github.com/kleo-53/music-system/internal/service/song"
+	albumStore "github.com/kleo-53/music-system/internal/store/album"
+	cacheStore "github.com/kleo-53/music-system/internal/store/cache"
+	groupStore "github.com/kleo-53/music-system/internal/store/group"
 	songStore "github.com/kleo-53/music-system/internal/store/song"
 	"github.com/kleo-53/music-system/pkg/logger"
 	"github.com/kleo-53/music-system/pkg/postgres"
@@ -42,17 +49,69 @@ func Run(cfg *config.Config) {
 		logger.Log().Fatal(ctx, "error with up migrations for database: %s", err.Error())
 		return
 	}
+	enricher, err := newDetailsProvider(cfg, cacheStore.New(pg))
+	if err != nil {
+		logger.Log().Fatal(ctx, "error with song details providers: %s", err.Error())
+		return
+	}
+	if enricher == nil {
+		logger.Log().Warn(ctx, "song details will not be requested: no providers are configured")
+	}
+
+	groupStore := groupStore.New(pg)
+	enrichmentQueue := songStore.NewEnrichmentQueue(pg)
 	songStore := songStore.New(pg)
-	songService := songService.New(songStore)
+	albumStore := albumStore.New(pg)
+	groupService := groupService.New(groupStore)
+	var songOpts []songService.Option
+	if cfg.FuzzyThreshold > 0 {
+		songOpts = append(songOpts, songService.FuzzyThreshold(cfg.FuzzyThreshold))
+	}
+	if cfg.TrashRetention > 0 {
+		songOpts = append(songOpts, songService.TrashRetention(cfg.TrashRetention))
+	}
+	if cfg.ImportBatchSize > 0 {
+		songOpts = append(songOpts, songService.ImportBatchSize(cfg.ImportBatchSize))
+	}
+	if enricher != nil {
+		songOpts = append(songOpts, songService.AsyncEnrichment())
+	}
+	songService := songService.New(songStore, groupStore, songOpts...)
+	albumService := albumService.New(albumStore, groupStore)
 
 	app := mux.NewRouter()
 	v1.NewRouter(
 		app,
 		songService,
+		groupService,
+		albumService,
+		cfg.AdminToken,
+		enricher,
 	)
 	server := &http.Server{
 		Addr: cfg.Port,
 	}
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	if enricher != nil {
+		var poolOpts []enrichment.Option
+		if cfg.EnrichWorkers > 0 {
+			poolOpts = append(poolOpts, enrichment.Workers(cfg.EnrichWorkers))
+		}
+		if cfg.EnrichMaxAttempts > 0 {
+			poolOpts = append(poolOpts, enrichment.MaxAttempts(cfg.EnrichMaxAttempts))
+		}
+		if cfg.EnrichRefresh >= 0 {
+			poolOpts = append(poolOpts, enrichment.RefreshInterval(cfg.EnrichRefresh))
+		}
+		pool := enrichment.New(enrichmentQueue, enricher, poolOpts...)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			pool.Run(workersCtx)
+		}()
+	}
+
 	logger.Log().Info(ctx, "server was started on %s", cfg.Port)
 	http.Handle("/", app)
 	go func() {
@@ -71,5 +130,7 @@ func Run(cfg *config.Config) {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Log().Fatal(shutdownCtx, "HTTP shutdown error: %v", err)
 	}
+	stopWorkers()
+	workers.Wait()
 	logger.Log().Info(ctx, "Graceful shutdown complete.")
 }
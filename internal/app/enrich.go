@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net/url"
+
+	"github.com/kleo-53/music-system/config"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/enrich"
+)
+
+// newDetailsProvider builds the chain of configured details providers, it is nil when there are none.
+// Every HTTP provider has its own cache, cacheStore is used with the postgres cache. File providers
+// are local and not cached, so edits of the file are seen at once.
+func newDetailsProvider(cfg *config.Config, cacheStore core.DetailsCacheStore) (enrich.DetailsProvider, error) {
+	var clientOpts []enrich.Option
+	if cfg.EnrichTimeout > 0 {
+		clientOpts = append(clientOpts, enrich.Timeout(cfg.EnrichTimeout))
+	}
+	if cfg.EnrichRetries >= 0 {
+		clientOpts = append(clientOpts, enrich.Retries(cfg.EnrichRetries))
+	}
+	var cacheOpts []enrich.CacheOption
+	if cfg.DetailsCacheSize > 0 {
+		cacheOpts = append(cacheOpts, enrich.CacheSize(cfg.DetailsCacheSize))
+	}
+	if cfg.DetailsCacheTTL > 0 {
+		cacheOpts = append(cacheOpts, enrich.CacheTTL(cfg.DetailsCacheTTL))
+	}
+	if cfg.DetailsCacheNegativeTTL > 0 {
+		cacheOpts = append(cacheOpts, enrich.CacheNegativeTTL(cfg.DetailsCacheNegativeTTL))
+	}
+	if cfg.DetailsCache == config.DetailsCachePostgres {
+		cacheOpts = append(cacheOpts, enrich.PersistentCache(cacheStore))
+	}
+	sources := cfg.DetailsProviders
+	if len(sources) == 0 {
+		if cfg.EnrichURL == "" {
+			return nil, nil
+		}
+		sources = []config.DetailsProvider{{Name: "api", Source: cfg.EnrichURL}}
+	}
+	var providers []enrich.DetailsProvider
+	for _, source := range sources {
+		var (
+			provider enrich.DetailsProvider
+			err      error
+		)
+		if u, parseErr := url.Parse(source.Source); parseErr == nil && (u.Scheme == "http" || u.Scheme == "https") {
+			provider, err = enrich.New(source.Source, append(clientOpts, enrich.Name(source.Name))...)
+			if err == nil && cfg.DetailsCache != config.DetailsCacheOff {
+				provider = enrich.NewCache(provider, cacheOpts...)
+			}
+		} else {
+			provider, err = enrich.NewFileProvider(source.Name, source.Source)
+		}
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return enrich.NewChain(providers, cfg.DetailsPrecedence)
+}
@@ -0,0 +1,112 @@
+// Package apperror describes domain errors shared by stores, services and the HTTP layer.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUpstream
+	KindPrecondition
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation failed"
+	case KindUpstream:
+		return "upstream failure"
+	case KindPrecondition:
+		return "precondition failed"
+	default:
+		return "internal error"
+	}
+}
+
+// Error is a domain error. Message is safe to show to API clients, Err keeps the cause for logs.
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+}
+
+var (
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrUpstream     = &Error{Kind: KindUpstream}
+	ErrPrecondition = &Error{Kind: KindPrecondition}
+)
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Kind.String()
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an Error of the same kind, so errors.Is(err, apperror.ErrNotFound) works for any not found error.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind
+}
+
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: KindNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: KindConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func Validation(format string, args ...any) error {
+	return &Error{Kind: KindValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+func PreconditionFailed(format string, args ...any) error {
+	return &Error{Kind: KindPrecondition, Message: fmt.Sprintf(format, args...)}
+}
+
+func Upstream(err error, format string, args ...any) error {
+	return &Error{Kind: KindUpstream, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// KindOf returns the kind of the first Error in the chain or KindInternal.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+// MessageOf returns the client-facing message of the first Error in the chain.
+func MessageOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		if e.Message != "" {
+			return e.Message
+		}
+		return e.Kind.String()
+	}
+	return KindInternal.String()
+}
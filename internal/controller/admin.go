@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+
+	"github.com/kleo-53/music-system/internal/enrich"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+const (
+	// adminTokenHeader carries the token configured by ADMIN_TOKEN.
+	adminTokenHeader = "X-Admin-Token"
+	// cacheBypassHeader makes an admin request ask the details providers again instead of the cache.
+	cacheBypassHeader = "X-Cache-Bypass"
+)
+
+// isAdmin reports whether the request has a valid admin token.
+func (ro *Router) isAdmin(r *http.Request) bool {
+	return ro.adminToken != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get(adminTokenHeader)), []byte(ro.adminToken)) == 1
+}
+
+// cacheBypass disables the details cache for admin requests with X-Cache-Bypass: true,
+// the header is rejected without a valid admin token. It affects only the details requested
+// while handling the request, the enrichment workers always use the cache.
+func (ro *Router) cacheBypass(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := r.Header.Get(cacheBypassHeader)
+		if value == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		bypass, err := strconv.ParseBool(value)
+		if err != nil {
+			logger.Log().Error(r.Context(), "Cache bypass rejected: invalid "+cacheBypassHeader+" header")
+			JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		if !bypass {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !ro.isAdmin(r) {
+			logger.Log().Error(r.Context(), "Cache bypass rejected: invalid admin token")
+			JSONProblem(w, r, http.StatusUnauthorized, "Invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(enrich.WithoutCache(r.Context())))
+	})
+}
+
+// requireAdmin allows the request only with a valid admin token. Admin endpoints are disabled when no token is configured.
+func (ro *Router) requireAdmin(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ro.adminToken == "" {
+			logger.Log().Error(r.Context(), "Admin request rejected: admin token is not configured")
+			JSONProblem(w, r, http.StatusForbidden, "Admin endpoints are disabled")
+			return
+		}
+		token := r.Header.Get(adminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(ro.adminToken)) != 1 {
+			logger.Log().Error(r.Context(), "Admin request rejected: invalid admin token")
+			JSONProblem(w, r, http.StatusUnauthorized, "Invalid admin token")
+			return
+		}
+		next(w, r)
+	})
+}
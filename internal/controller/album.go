@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+// @Summary		Get albums
+// @Description	Get all albums with their tracklists, pagination and optional filters
+// @Tags		albums
+// @Accept		json
+// @Produce		json
+// @Param		group_id	query		int			false	"Filter by group ID"
+// @Param		group		query		string		false	"Filter by group name"
+// @Param		title		query		string		false	"Filter by album title"
+// @Param		song_id		query		int			false	"Filter by song on the album"
+// @Param		page		query		int			false	"Page number" 				default(1)
+// @Param		page_size	query		int 		false 	"Number of albums per page, at most 100" default(10)
+// @Success		200			{object} 	[]model.Album
+// @Failure		400			{object} 	model.Problem 	"Invalid request payload"
+// @Failure		500			{object} 	model.Problem 	"Failed to get any albums data"
+// @Router		/api/v1/albums [get]
+func (ro *Router) getAlbums(w http.ResponseWriter, r *http.Request) {
+	filters := model.AlbumFilters{
+		Group: r.URL.Query().Get("group"),
+		Title: r.URL.Query().Get("title"),
+	}
+	if groupID := r.URL.Query().Get("group_id"); groupID != "" {
+		group_id, err := strconv.ParseInt(groupID, 10, strconv.IntSize)
+		if err != nil {
+			logger.Log().Error(r.Context(), "Failed to get any albums data: invalid group id provided")
+			JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		filters.GroupID = int(group_id)
+	}
+	if songID := r.URL.Query().Get("song_id"); songID != "" {
+		song_id, err := strconv.ParseInt(songID, 10, strconv.IntSize)
+		if err != nil {
+			logger.Log().Error(r.Context(), "Failed to get any albums data: invalid song id provided")
+			JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		filters.SongID = int(song_id)
+	}
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any albums data: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	albums, err := ro.albumService.GetAlbums(r.Context(), filters, page, pageSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any albums data: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get albums data")
+	JSONResponse(r.Context(), w, http.StatusOK, albums)
+}
+
+// @Summary 	Get album
+// @Description	Get album with its tracklist by ID
+// @Tags 		albums
+// @Produce 	json
+// @Param 		album_id 	path 		int 				true 	"Album ID"
+// @Success 	200 		{object} 	model.Album
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Album not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get album"
+// @Router 		/api/v1/albums/{album_id} [get]
+func (ro *Router) getAlbum(w http.ResponseWriter, r *http.Request) {
+	album_id, err := strconv.ParseInt(mux.Vars(r)["album_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get album: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	album, err := ro.albumService.GetAlbum(r.Context(), int(album_id))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get album: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get album")
+	JSONResponse(r.Context(), w, http.StatusOK, album)
+}
+
+// @Summary 	Add album
+// @Description	Add a new album; the group is found by name or created
+// @Tags 		albums
+// @Accept 		json
+// @Produce 	json
+// @Param 		body 	body 		model.AlbumCommon 	true 				"New album data"
+// @Success 	201 	{object} 	model.Album
+// @Failure 	400 	{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	422 	{object} 	model.Problem 	"Invalid input"
+// @Failure 	500 	{object} 	model.Problem 	"Failed to add album"
+// @Router 		/api/v1/albums [post]
+func (ro *Router) addAlbum(w http.ResponseWriter, r *http.Request) {
+	var req model.AlbumCommon
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to add album: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	album, err := ro.albumService.CreateAlbum(r.Context(), req)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to add album: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Album was added")
+	JSONResponse(r.Context(), w, http.StatusCreated, album)
+}
+
+// @Summary 	Update album
+// @Description	Update album information by ID as a JSON Merge Patch: absent fields are kept, null clears the release date
+// @Tags 		albums
+// @Accept 		json
+// @Produce 	json
+// @Param 		album_id 	path 		int 				true 	"Album ID"
+// @Param 		body 		body 		model.AlbumPatch 	true 	"Fields to change"
+// @Success 	200 		{object} 	map[string]string 	"Album was updated"
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Album not found"
+// @Failure 	422 		{object} 	model.Problem 	"Invalid input"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to update album"
+// @Router 		/api/v1/albums/{album_id} [patch]
+func (ro *Router) updateAlbum(w http.ResponseWriter, r *http.Request) {
+	album_id, err := strconv.ParseInt(mux.Vars(r)["album_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to update album: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	var req model.AlbumPatch
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to update album: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := ro.albumService.UpdateAlbum(r.Context(), int(album_id), req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to update album: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Album updated successfully")
+	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Album was updated"})
+}
+
+// @Summary 	Set album tracks
+// @Description	Replace the tracklist of the album with existing songs in the given order
+// @Tags 		albums
+// @Accept 		json
+// @Produce 	json
+// @Param 		album_id 	path 		int 				true 	"Album ID"
+// @Param 		body 		body 		model.AlbumTracks 	true 	"Song IDs in track order"
+// @Success 	200 		{object} 	map[string]string 	"Album tracks were updated"
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Album not found"
+// @Failure 	422 		{object} 	model.Problem 	"Invalid or unknown songs"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to update album tracks"
+// @Router 		/api/v1/albums/{album_id}/tracks [put]
+func (ro *Router) setAlbumTracks(w http.ResponseWriter, r *http.Request) {
+	album_id, err := strconv.ParseInt(mux.Vars(r)["album_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to update album tracks: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	var req model.AlbumTracks
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to update album tracks: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := ro.albumService.SetAlbumTracks(r.Context(), int(album_id), req.SongIDs); err != nil {
+		logger.Log().Error(r.Context(), "Failed to update album tracks: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Album tracks updated successfully")
+	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Album tracks were updated"})
+}
+
+// @Summary 	Delete album
+// @Description	Delete an album by ID, its songs are kept
+// @Tags 		albums
+// @Produce 	json
+// @Param 		album_id 	path 		int 				true 	"Album ID"
+// @Success		200 		{object} 	map[string]string 	"Album was deleted"
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Album not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to delete album"
+// @Router 		/api/v1/albums/{album_id} [delete]
+func (ro *Router) deleteAlbum(w http.ResponseWriter, r *http.Request) {
+	album_id, err := strconv.ParseInt(mux.Vars(r)["album_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to delete album: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := ro.albumService.DeleteAlbum(r.Context(), int(album_id)); err != nil {
+		logger.Log().Error(r.Context(), "Failed to delete album: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Album deleted successfully")
+	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Album was deleted"})
+}
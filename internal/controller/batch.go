@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/enrich"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+const (
+	// enrichWorkers limits concurrent requests to the external API during an import.
+	enrichWorkers = 8
+	// maxImportLine is the longest NDJSON line, lyrics can be long.
+	maxImportLine = 1 << 20
+	// maxImportBody limits the whole import request.
+	maxImportBody = 64 << 20
+)
+
+// @Summary 	Import songs
+// @Description	Create many songs at once from a JSON array or NDJSON (one song per line). Songs are inserted in batches
+// @Description	and every item gets its own result, so invalid items do not stop the import. Missing details are requested
+// @Description	by the enrichment workers after the import (defer, the default), from the external API before inserting (sync)
+// @Description	or not at all (none). With sync the request waits for the external API, which can take long for big imports
+// @Tags 		songs
+// @Accept 		json
+// @Accept 		application/x-ndjson
+// @Produce 	json
+// @Param 		enrich 	query 		string 				false 	"When to request missing details" Enums(defer, sync, none) default(defer)
+// @Param 		body 	body 		[]model.SongImport 	true 	"Songs to import, at most 10000"
+// @Param 		X-Cache-Bypass 	header 	bool 	false 	"Skip the details cache with enrich=sync, requires X-Admin-Token. The enrichment workers always use the cache"
+// @Success 	200 	{object} 	model.SongImportReport
+// @Failure 	400 	{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	413 	{object} 	model.Problem 	"Request is larger than 64 MB"
+// @Failure 	422 	{object} 	model.Problem 	"No songs or too many songs"
+// @Failure 	500 	{object} 	model.Problem 	"Failed to import songs"
+// @Router 		/api/v1/songs:batch [post]
+func (ro *Router) importSongs(w http.ResponseWriter, r *http.Request) {
+	enrich := r.URL.Query().Get("enrich")
+	switch enrich {
+	case "":
+		enrich = model.EnrichDefer
+	case model.EnrichSync, model.EnrichDefer, model.EnrichNone:
+	default:
+		logger.Log().Error(r.Context(), "Failed to import songs: unknown enrich mode "+enrich)
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	songs, invalid, err := decodeImport(http.MaxBytesReader(w, r.Body, maxImportBody))
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		logger.Log().Error(r.Context(), "Failed to import songs: "+err.Error())
+		JSONProblem(w, r, http.StatusRequestEntityTooLarge, "Request is larger than 64 MB")
+		return
+	case apperror.KindOf(err) == apperror.KindValidation:
+		logger.Log().Error(r.Context(), "Failed to import songs: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	case err != nil:
+		logger.Log().Error(r.Context(), "Failed to import songs: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if enrich == model.EnrichSync {
+		ro.enrichImport(r.Context(), songs, invalid)
+	}
+	report, err := ro.songService.ImportSongs(r.Context(), songs, invalid, enrich == model.EnrichDefer)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to import songs: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Imported %d songs, %d failed", report.Created, report.Failed)
+	JSONResponse(r.Context(), w, http.StatusOK, report)
+}
+
+// decodeImport reads a JSON array or NDJSON and stops once there are more than model.MaxImportSongs songs.
+// An array element or NDJSON line that is not a valid song fails only its own item, invalid maps its index
+// to the error. Broken JSON syntax of the array fails the whole request, since the next elements cannot be found.
+func decodeImport(body io.Reader) (songs []model.SongImport, invalid map[int]error, err error) {
+	tooMany := apperror.Validation("at most %d songs can be imported at once", model.MaxImportSongs)
+	reader := bufio.NewReader(body)
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			break
+		}
+		if _, err := reader.ReadByte(); err != nil {
+			return nil, nil, err
+		}
+	}
+	invalid = map[int]error{}
+	if b, _ := reader.Peek(1); b[0] == '[' {
+		decoder := json.NewDecoder(reader)
+		if _, err := decoder.Token(); err != nil {
+			return nil, nil, err
+		}
+		for decoder.More() {
+			if len(songs) == model.MaxImportSongs {
+				return nil, nil, tooMany
+			}
+			var item json.RawMessage
+			if err := decoder.Decode(&item); err != nil {
+				return nil, nil, err
+			}
+			var song model.SongImport
+			if err := json.Unmarshal(item, &song); err != nil {
+				invalid[len(songs)] = err
+			}
+			songs = append(songs, song)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, nil, err
+		}
+		return songs, invalid, nil
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(songs) == model.MaxImportSongs {
+			return nil, nil, tooMany
+		}
+		var song model.SongImport
+		if err := json.Unmarshal(line, &song); err != nil {
+			invalid[len(songs)] = err
+		}
+		songs = append(songs, song)
+	}
+	return songs, invalid, scanner.Err()
+}
+
+// enrichImport fills missing details of the songs from the external API before they are inserted.
+func (ro *Router) enrichImport(ctx context.Context, songs []model.SongImport, invalid map[int]error) {
+	forEachMissing(songs, func(i int) {
+		if _, ok := invalid[i]; ok {
+			return
+		}
+		details, err := ro.getSongDetails(ctx, songs[i].Group, songs[i].Song)
+		if errors.Is(err, enrich.ErrNotFound) {
+			songs[i].NotFound = true
+			return
+		}
+		if err != nil {
+			logger.Log().Warn(ctx, "Failed to get song details for import item %d: %s", i, err.Error())
+			return
+		}
+		fillImport(&songs[i], details)
+	})
+}
+
+// forEachMissing calls fn concurrently for the songs that lack some details.
+func forEachMissing(songs []model.SongImport, fn func(i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, enrichWorkers)
+	for i, song := range songs {
+		if song.Text != "" && song.ReleaseDate != "" && song.Link != "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// fillImport keeps details given in the request and takes the rest from the external API.
+func fillImport(song *model.SongImport, details model.SongDetail) {
+	fill := func(value *string, detail, filled string) {
+		if *value != "" || filled == "" {
+			return
+		}
+		*value = filled
+		if source := details.Sources[detail]; source != "" {
+			if song.Sources == nil {
+				song.Sources = map[string]string{}
+			}
+			song.Sources[detail] = source
+		}
+	}
+	fill(&song.Text, model.DetailText, details.Text)
+	fill(&song.ReleaseDate, model.DetailReleaseDate, details.ReleaseDate)
+	fill(&song.Link, model.DetailLink, details.Link)
+}
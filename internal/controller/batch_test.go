@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func TestDecodeImport(t *testing.T) {
+	muse := model.SongImport{Group: "Muse", Song: "Uprising"}
+	ddt := model.SongImport{Group: "ДДТ", Song: "Осень", Text: "Что такое осень"}
+	tests := []struct {
+		name        string
+		body        string
+		want        []model.SongImport
+		wantInvalid []int
+		wantErr     bool
+	}{
+		{
+			name: "array",
+			body: ` [{"group": "Muse", "song": "Uprising"}, {"group": "ДДТ", "song": "Осень", "text": "Что такое осень"}]`,
+			want: []model.SongImport{muse, ddt},
+		},
+		{
+			name: "ndjson",
+			body: "{\"group\": \"Muse\", \"song\": \"Uprising\"}\n{\"group\": \"ДДТ\", \"song\": \"Осень\", \"text\": \"Что такое осень\"}",
+			want: []model.SongImport{muse, ddt},
+		},
+		{
+			name: "ndjson with blank lines",
+			body: "\r\n\n{\"group\": \"Muse\", \"song\": \"Uprising\"}\r\n  \n\n{\"group\": \"ДДТ\", \"song\": \"Осень\", \"text\": \"Что такое осень\"}\n\n",
+			want: []model.SongImport{muse, ddt},
+		},
+		{
+			name:        "malformed array item",
+			body:        `[{"group": "Muse", "song": "Uprising"}, {"group": "Muse", "song": 1}, "song", {"group": "ДДТ", "song": "Осень", "text": "Что такое осень"}]`,
+			want:        []model.SongImport{muse, {Group: "Muse"}, {}, ddt},
+			wantInvalid: []int{1, 2},
+		},
+		{
+			name:        "malformed ndjson line",
+			body:        "{\"group\": \"Muse\", \"song\": \"Uprising\"}\n{\"group\": \n{\"group\": \"ДДТ\", \"song\": \"Осень\", \"text\": \"Что такое осень\"}",
+			want:        []model.SongImport{muse, {}, ddt},
+			wantInvalid: []int{1},
+		},
+		{name: "empty array", body: "[]", want: nil},
+		{name: "broken array", body: `[{"group": "Muse", "song": "Uprising"}, {"group": `, wantErr: true},
+		{name: "empty body", body: " \n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, invalid, err := decodeImport(strings.NewReader(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeImport() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeImport() error = %v", err)
+			}
+			if !reflect.DeepEqual(songs, tt.want) {
+				t.Errorf("decodeImport() songs = %+v, want %+v", songs, tt.want)
+			}
+			var indexes []int
+			for i := range invalid {
+				indexes = append(indexes, i)
+			}
+			sort.Ints(indexes)
+			if !reflect.DeepEqual(indexes, tt.wantInvalid) {
+				t.Errorf("decodeImport() invalid items = %v, want %v", indexes, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestDecodeImportTooMany(t *testing.T) {
+	item := `{"group": "Muse", "song": "Uprising"}`
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "array at the limit", body: "[" + strings.Repeat(item+",", model.MaxImportSongs-1) + item + "]"},
+		{name: "array over the limit", body: "[" + strings.Repeat(item+",", model.MaxImportSongs) + item + "]", wantErr: true},
+		{name: "ndjson at the limit", body: strings.Repeat(item+"\n\n", model.MaxImportSongs)},
+		{name: "ndjson over the limit", body: strings.Repeat(item+"\n", model.MaxImportSongs+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, _, err := decodeImport(strings.NewReader(tt.body))
+			if tt.wantErr {
+				if apperror.KindOf(err) != apperror.KindValidation {
+					t.Fatalf("decodeImport() error = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil || len(songs) != model.MaxImportSongs {
+				t.Errorf("decodeImport() = %d songs, %v, want %d songs", len(songs), err, model.MaxImportSongs)
+			}
+		})
+	}
+}
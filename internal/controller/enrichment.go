@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/enrich"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+// parseOverwrite reads the overwrite flag of the re-enrich endpoints, details are only filled by default.
+func parseOverwrite(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("overwrite")
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// @Summary 	Enrich song
+// @Description	Request the details of the song from the external API again and save them. By default only empty details are filled, overwrite=true replaces the filled ones too.
+// @Description	A song the external API does not know keeps its details and gets the enrichment status not_found
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Param 		overwrite 	query 		bool 		false 	"Replace details that are already filled" default(false)
+// @Param 		X-Cache-Bypass 	header 	bool 	false 	"Skip the details cache, requires X-Admin-Token"
+// @Success		200 		{object} 	model.Song
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	401 		{object} 	model.Problem 	"Invalid admin token"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to enrich song"
+// @Failure 	502 		{object} 	model.Problem 	"External API is unavailable"
+// @Failure 	503 		{object} 	model.Problem 	"External API is not configured"
+// @Router 		/api/v1/songs/{song_id}/enrich [post]
+func (ro *Router) enrichSong(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to enrich song: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	overwrite, err := parseOverwrite(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to enrich song: invalid overwrite provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if ro.enricher == nil {
+		logger.Log().Error(r.Context(), "Failed to enrich song: external api is not configured")
+		JSONProblem(w, r, http.StatusServiceUnavailable, "External API is not configured")
+		return
+	}
+	song, err := ro.songService.GetSong(r.Context(), int(song_id))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to enrich song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	details, err := ro.getSongDetails(r.Context(), song.Group, song.Song)
+	switch {
+	case errors.Is(err, enrich.ErrNotFound):
+		song, err = ro.songService.MarkSongNotFound(r.Context(), song.ID)
+	case err != nil:
+		logger.Log().Error(r.Context(), "Failed to enrich song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	default:
+		song, err = ro.songService.EnrichSong(r.Context(), song.ID, details, overwrite)
+	}
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to enrich song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Song %d was enriched", song.ID)
+	setSongETag(w, song)
+	JSONResponse(r.Context(), w, http.StatusOK, song)
+}
+
+// @Summary 	Enrich songs
+// @Description	Queue every song missing any of the given details for the background enrichment workers. Songs that already wait for enrichment are skipped, songs with the enrichment status skipped or not_found are queued too.
+// @Description	Requires the admin token, with overwrite the details edited by users are replaced across the library
+// @Tags 		songs
+// @Produce 	json
+// @Param 		X-Admin-Token 	header 		string 		true 	"Admin token"
+// @Param 		missing 	query 		string 		false 	"Comma separated details: text, releaseDate, link; all by default"
+// @Param 		overwrite 	query 		bool 		false 	"Replace details that are already filled" default(false)
+// @Success		202 		{object} 	model.EnrichmentQueued
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	401 		{object} 	model.Problem 	"Invalid admin token"
+// @Failure 	403 		{object} 	model.Problem 	"Admin endpoints are disabled"
+// @Failure 	422 		{object} 	model.Problem 	"Unknown detail"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to queue songs"
+// @Failure 	503 		{object} 	model.Problem 	"External API is not configured"
+// @Router 		/api/v1/songs:enrich [post]
+func (ro *Router) enrichSongs(w http.ResponseWriter, r *http.Request) {
+	overwrite, err := parseOverwrite(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to queue songs: invalid overwrite provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if ro.enricher == nil {
+		logger.Log().Error(r.Context(), "Failed to queue songs: external api is not configured")
+		JSONProblem(w, r, http.StatusServiceUnavailable, "External API is not configured")
+		return
+	}
+	var missing []string
+	for _, detail := range strings.Split(r.URL.Query().Get("missing"), ",") {
+		if detail = strings.TrimSpace(detail); detail != "" {
+			missing = append(missing, detail)
+		}
+	}
+	queued, err := ro.songService.EnqueueEnrichment(r.Context(), missing, overwrite)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to queue songs: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "%d songs were queued for enrichment", queued)
+	JSONResponse(r.Context(), w, http.StatusAccepted, model.EnrichmentQueued{Queued: queued})
+}
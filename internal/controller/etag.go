@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// songETag is a strong entity tag made of the song version.
+func songETag(song model.Song) string {
+	return `"` + strconv.Itoa(song.Version) + `"`
+}
+
+func setSongETag(w http.ResponseWriter, song model.Song) {
+	w.Header().Set("ETag", songETag(song))
+}
+
+// ifMatchVersions returns the song versions listed in If-Match. It is nil when the header is absent or "*",
+// weak and malformed tags never match with strong comparison, so they are skipped.
+func ifMatchVersions(r *http.Request) []int {
+	header := r.Header.Get("If-Match")
+	if strings.TrimSpace(header) == "" || strings.TrimSpace(header) == "*" {
+		return nil
+	}
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if version, ok := parseETag(tag); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// notModified reports whether If-None-Match matches the song with weak comparison.
+func notModified(r *http.Request, song model.Song) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" {
+			return true
+		}
+		if version, ok := parseETag(tag); ok && version == song.Version {
+			return true
+		}
+	}
+	return false
+}
+
+func parseETag(tag string) (int, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	return version, err == nil
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func TestSongETag(t *testing.T) {
+	if got := songETag(model.Song{Version: 12}); got != `"12"` {
+		t.Errorf("songETag() = %s, want \"12\"", got)
+	}
+}
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []int
+	}{
+		{name: "absent", header: "", want: nil},
+		{name: "any version", header: " * ", want: nil},
+		{name: "one tag", header: `"3"`, want: []int{3}},
+		{name: "list of tags", header: `"3", "5" ,"8"`, want: []int{3, 5, 8}},
+		{name: "weak tags never match", header: `W/"3", "5"`, want: []int{5}},
+		{name: "only weak tags", header: `W/"3"`, want: []int{}},
+		{name: "malformed tags are skipped", header: `3, "x", "4`, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/api/v1/songs/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			if got := ifMatchVersions(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ifMatchVersions(%q) = %#v, want %#v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	song := model.Song{ID: 1, Version: 3}
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "absent", header: "", want: false},
+		{name: "same version", header: `"3"`, want: true},
+		{name: "weak tag of the same version", header: `W/"3"`, want: true},
+		{name: "other version", header: `"2"`, want: false},
+		{name: "list with the version", header: `"1", W/"2", "3"`, want: true},
+		{name: "any version", header: `*`, want: true},
+		{name: "malformed", header: `3`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/songs/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-None-Match", tt.header)
+			}
+			if got := notModified(r, song); got != tt.want {
+				t.Errorf("notModified(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreconditionFailedResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/songs/1", nil)
+	ErrorResponse(w, r, apperror.PreconditionFailed("song %d was changed, its current version is %d", 1, 4))
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("ErrorResponse() status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+}
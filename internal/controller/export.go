@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+const (
+	exportCSV    = "csv"
+	exportNDJSON = "ndjson"
+	exportJSON   = "json"
+
+	// exportFlushEvery is how many songs are written between flushes to the client.
+	exportFlushEvery = 100
+)
+
+var csvHeader = []string{"id", "group_id", "group", "song", "release_date", "link", "text"}
+
+// songExporter writes songs in one of the export formats. Headers are sent with the first song,
+// so errors found before any song was read are still reported as problems.
+type songExporter struct {
+	w       http.ResponseWriter
+	format  string
+	started bool
+	count   int
+	csv     *csv.Writer
+	json    *json.Encoder
+}
+
+func (e *songExporter) start() {
+	e.started = true
+	switch e.format {
+	case exportCSV:
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	case exportNDJSON:
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		e.w.Header().Set("Content-Type", "application/json")
+	}
+	e.w.Header().Set("Content-Disposition", `attachment; filename="songs.`+e.format+`"`)
+	e.w.WriteHeader(http.StatusOK)
+	switch e.format {
+	case exportCSV:
+		e.csv = csv.NewWriter(e.w)
+		_ = e.csv.Write(csvHeader)
+	case exportJSON:
+		_, _ = e.w.Write([]byte("["))
+	}
+	e.json = json.NewEncoder(e.w)
+}
+
+func (e *songExporter) write(song model.Song) error {
+	if !e.started {
+		e.start()
+	}
+	var err error
+	switch e.format {
+	case exportCSV:
+		err = e.csv.Write([]string{
+			strconv.Itoa(song.ID),
+			strconv.Itoa(song.GroupID),
+			song.Group,
+			song.Song,
+			song.ReleaseDate,
+			song.Link,
+			song.Text,
+		})
+	case exportJSON:
+		if e.count > 0 {
+			if _, err = e.w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		err = e.json.Encode(song)
+	default:
+		err = e.json.Encode(song)
+	}
+	if err != nil {
+		return err
+	}
+	e.count++
+	if e.count%exportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// finish closes the document, an empty export is still a valid document.
+func (e *songExporter) finish() error {
+	if !e.started {
+		e.start()
+	}
+	if e.format == exportJSON {
+		if _, err := e.w.Write([]byte("]\n")); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *songExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// @Summary		Export songs
+// @Description	Download all songs matching the filters of the song listing without pagination.
+// @Description	Songs are streamed from the database, so the export of the whole library does not need much memory
+// @Tags		songs
+// @Produce		json
+// @Produce		text/csv
+// @Produce		application/x-ndjson
+// @Param		format			query		string		false  "Export format" Enums(csv, ndjson, json) default(json)
+// @Param		group_id		query		int			false  "Filter by group ID"
+// @Param		group			query		string		false  "Filter by group name"
+// @Param		song			query		string		false  "Filter by song name"
+// @Param		text			query		string		false  "Filter by words in the text"
+// @Param		release_date	query		string		false  "Filter by release date"
+// @Param		link			query		string		false  "Filter by link"
+// @Param		album_id		query		int			false  "Filter by album ID, songs are returned in track order"
+// @Param		match			query		string		false  "How group and song filters match" Enums(exact, fuzzy) default(exact)
+// @Param		threshold		query		number		false  "Minimal similarity for fuzzy match, from 0 to 1"
+// @Param		sort			query		string		false  "Comma separated sort keys: group, song, release_date, id; prefix with - or add :desc for descending order"
+// @Success		200				{array} 	model.Song
+// @Failure		400				{object} 	model.Problem 	"Invalid request payload"
+// @Failure		422				{object} 	model.Problem 	"Invalid match mode, threshold or sort key"
+// @Failure		500				{object} 	model.Problem 	"Failed to export songs"
+// @Router		/api/v1/songs/export [get]
+func (ro *Router) exportSongs(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = exportJSON
+	case exportCSV, exportNDJSON, exportJSON:
+	default:
+		logger.Log().Error(r.Context(), "Failed to export songs: unknown format "+format)
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	filters, err := parseSongFilters(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to export songs: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	exporter := &songExporter{w: w, format: format}
+	if err := ro.songService.ExportSongs(r.Context(), filters, exporter.write); err != nil {
+		logger.Log().Error(r.Context(), "Failed to export songs: "+err.Error())
+		if !exporter.started {
+			ErrorResponse(w, r, err)
+		}
+		return
+	}
+	if err := exporter.finish(); err != nil {
+		logger.Log().Error(r.Context(), "Failed to export songs: "+err.Error())
+		return
+	}
+	logger.Log().Info(r.Context(), "Exported %d songs", exporter.count)
+}
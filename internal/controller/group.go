@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+// @Summary		Get groups
+// @Description	Get all groups with pagination and optional filter by name
+// @Tags		groups
+// @Accept		json
+// @Produce		json
+// @Param		name		query		string		false	"Filter by group name"
+// @Param		page		query		int			false	"Page number" 				default(1)
+// @Param		page_size	query		int 		false 	"Number of groups per page, at most 100" default(10)
+// @Success		200			{object} 	[]model.Group
+// @Failure		400			{object} 	model.Problem 	"Invalid request payload"
+// @Failure		500			{object} 	model.Problem 	"Failed to get any groups data"
+// @Router		/api/v1/groups [get]
+func (ro *Router) getGroups(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any groups data: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	groups, err := ro.groupService.GetGroups(r.Context(), r.URL.Query().Get("name"), page, pageSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any groups data: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get groups data")
+	JSONResponse(r.Context(), w, http.StatusOK, groups)
+}
+
+// @Summary 	Get group
+// @Description	Get group by ID
+// @Tags 		groups
+// @Produce 	json
+// @Param 		group_id 	path 		int 				true 	"Group ID"
+// @Success 	200 		{object} 	model.Group
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Group not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get group"
+// @Router 		/api/v1/groups/{group_id} [get]
+func (ro *Router) getGroup(w http.ResponseWriter, r *http.Request) {
+	group_id, err := strconv.ParseInt(mux.Vars(r)["group_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get group: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	group, err := ro.groupService.GetGroup(r.Context(), int(group_id))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get group: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get group")
+	JSONResponse(r.Context(), w, http.StatusOK, group)
+}
+
+// @Summary 	Add group
+// @Description	Add a new group to the system
+// @Tags 		groups
+// @Accept 		json
+// @Produce 	json
+// @Param 		body 	body 		model.GroupCommon 	true 				"New group data"
+// @Success 	201 	{object} 	model.Group
+// @Failure 	400 	{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	409 	{object} 	model.Problem 	"Group already exists"
+// @Failure 	422 	{object} 	model.Problem 	"Invalid input"
+// @Failure 	500 	{object} 	model.Problem 	"Failed to add group"
+// @Router 		/api/v1/groups [post]
+func (ro *Router) addGroup(w http.ResponseWriter, r *http.Request) {
+	var req model.GroupCommon
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to add group: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	group, err := ro.groupService.CreateGroup(r.Context(), req.Name)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to add group: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Group was added")
+	JSONResponse(r.Context(), w, http.StatusCreated, group)
+}
+
+// @Summary 	Update group
+// @Description	Rename group by ID
+// @Tags 		groups
+// @Accept 		json
+// @Produce 	json
+// @Param 		group_id 	path 		int 				true 	"Group ID"
+// @Param 		body 		body 		model.GroupCommon 	true 	"Updated group data"
+// @Success 	200 		{object} 	map[string]string 	"Group was updated"
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Group not found"
+// @Failure 	409 		{object} 	model.Problem 	"Group already exists"
+// @Failure 	422 		{object} 	model.Problem 	"Invalid input"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to update group"
+// @Router 		/api/v1/groups/{group_id} [patch]
+func (ro *Router) updateGroup(w http.ResponseWriter, r *http.Request) {
+	group_id, err := strconv.ParseInt(mux.Vars(r)["group_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to update group: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	var req model.GroupCommon
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to update group: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := ro.groupService.UpdateGroup(r.Context(), int(group_id), req.Name); err != nil {
+		logger.Log().Error(r.Context(), "Failed to update group: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Group updated successfully")
+	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Group was updated"})
+}
+
+// @Summary 	Delete group
+// @Description	Delete a group by ID. Groups that still have songs cannot be deleted
+// @Tags 		groups
+// @Produce 	json
+// @Param 		group_id 	path 		int 				true 	"Group ID"
+// @Success		200 		{object} 	map[string]string 	"Group was deleted"
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Group not found"
+// @Failure 	409 		{object} 	model.Problem 	"Group still has songs or albums"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to delete group"
+// @Router 		/api/v1/groups/{group_id} [delete]
+func (ro *Router) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	group_id, err := strconv.ParseInt(mux.Vars(r)["group_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to delete group: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := ro.groupService.DeleteGroup(r.Context(), int(group_id)); err != nil {
+		logger.Log().Error(r.Context(), "Failed to delete group: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Group deleted successfully")
+	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Group was deleted"})
+}
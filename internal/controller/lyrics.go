@@ -0,0 +1,340 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+// @Summary 	Get song lyrics
+// @Description	Get the text of the song split into sections (intro, verse, chorus, bridge, outro) with repeats. Markers like [Chorus] or x2 are recognized, unmarked stanzas sung more than once are choruses
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Param 		collapse 	query 		bool 		false 	"Drop the lines of repeated sections, they keep repeatOf" default(false)
+// @Success 	200 		{object} 	model.Lyrics
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get song lyrics"
+// @Router 		/api/v1/songs/{song_id}/lyrics [get]
+func (ro *Router) getLyrics(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song lyrics: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	collapse := false
+	if value := r.URL.Query().Get("collapse"); value != "" {
+		if collapse, err = strconv.ParseBool(value); err != nil {
+			logger.Log().Error(r.Context(), "Failed to get song lyrics: invalid collapse provided")
+			JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+	var lyrics model.Lyrics
+	lyrics, err = ro.songService.GetLyrics(r.Context(), int(song_id), collapse)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song lyrics: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get song lyrics")
+	JSONResponse(r.Context(), w, http.StatusOK, lyrics)
+}
+
+// maxLRCSize limits uploaded LRC files.
+const maxLRCSize = 1 << 20
+
+// lyricsContentTypes are the content types of the synced lyrics exports.
+var lyricsContentTypes = map[string]string{
+	model.LyricsLRC: "text/plain; charset=utf-8",
+	model.LyricsVTT: "text/vtt; charset=utf-8",
+	model.LyricsSRT: "application/x-subrip; charset=utf-8",
+}
+
+// @Summary 	Upload synced lyrics
+// @Description	Replace the time-synced lyrics of the song with LRC, enhanced LRC with word timestamps like <00:12.50> is supported
+// @Tags 		songs
+// @Accept 		plain
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Param 		body 		body 		string 		true 	"LRC file"
+// @Success 	200 		{object} 	model.SyncedLyrics
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	413 		{object} 	model.Problem 	"LRC is larger than 1 MB"
+// @Failure 	422 		{object} 	model.Problem 	"Invalid LRC"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to upload synced lyrics"
+// @Router 		/api/v1/songs/{song_id}/lyrics.lrc [put]
+func (ro *Router) putSyncedLyrics(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to upload synced lyrics: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLRCSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		logger.Log().Error(r.Context(), "Failed to upload synced lyrics: "+err.Error())
+		JSONProblem(w, r, http.StatusRequestEntityTooLarge, "LRC is larger than 1 MB")
+		return
+	}
+	if err != nil || !utf8.Valid(body) {
+		logger.Log().Error(r.Context(), "Failed to upload synced lyrics: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	synced, err := ro.songService.PutSyncedLyrics(r.Context(), int(song_id), string(body))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to upload synced lyrics: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Synced lyrics of song %d were uploaded", synced.SongID)
+	JSONResponse(r.Context(), w, http.StatusOK, synced)
+}
+
+// @Summary 	Export synced lyrics
+// @Description	Get the synced lyrics of the song as LRC (as uploaded), WebVTT or SubRip subtitles
+// @Tags 		songs
+// @Produce 	plain
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Param 		format 		path 		string 		true 	"Export format" Enums(lrc, vtt, srt)
+// @Success 	200 		{string} 	string
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song or its synced lyrics not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to export synced lyrics"
+// @Router 		/api/v1/songs/{song_id}/lyrics.{format} [get]
+func (ro *Router) exportSyncedLyrics(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to export synced lyrics: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	format := mux.Vars(r)["format"]
+	text, err := ro.songService.ExportSyncedLyrics(r.Context(), int(song_id), format)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to export synced lyrics: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Export synced lyrics as %s", format)
+	w.Header().Set("Content-Type", lyricsContentTypes[format])
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, text); err != nil {
+		logger.Log().Error(r.Context(), "Failed to write synced lyrics: "+err.Error())
+	}
+}
+
+// @Summary 	Get synced lyrics
+// @Description	Get the time-synced lyrics of the song as lines with their times
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Success 	200 		{object} 	model.SyncedLyrics
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song or its synced lyrics not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get synced lyrics"
+// @Router 		/api/v1/songs/{song_id}/lyrics/synced [get]
+func (ro *Router) getSyncedLyrics(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get synced lyrics: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	synced, err := ro.songService.GetSyncedLyrics(r.Context(), int(song_id))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get synced lyrics: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get synced lyrics")
+	JSONResponse(r.Context(), w, http.StatusOK, synced)
+}
+
+// @Summary 	Get active line
+// @Description	Get the line of the synced lyrics sung at the playback offset, the word too for enhanced LRC
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Param 		at 			query 		string 		true 	"Playback offset: seconds (83.5), a duration (1m23.5s) or mm:ss.xx (01:23.50)"
+// @Success 	200 		{object} 	model.ActiveLine
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song or its synced lyrics not found"
+// @Failure 	422 		{object} 	model.Problem 	"Negative offset"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get active line"
+// @Router 		/api/v1/songs/{song_id}/lyrics/active [get]
+func (ro *Router) getActiveLine(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get active line: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	at, err := parsePlaybackOffset(r.URL.Query().Get("at"))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get active line: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	line, err := ro.songService.GetActiveLine(r.Context(), int(song_id), at)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get active line: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get active line")
+	JSONResponse(r.Context(), w, http.StatusOK, line)
+}
+
+// parsePlaybackOffset accepts seconds (83.5), Go durations (1m23.5s) and LRC times (01:23.50).
+func parsePlaybackOffset(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, errors.New("playback offset is required")
+	}
+	invalid := fmt.Errorf("invalid playback offset %q", value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		at, ok := secondsDuration(seconds)
+		if !ok {
+			return 0, invalid
+		}
+		return at, nil
+	}
+	if minutes, seconds, ok := strings.Cut(value, ":"); ok {
+		m, err := strconv.ParseUint(minutes, 10, 32)
+		if err != nil {
+			return 0, invalid
+		}
+		s, err := strconv.ParseFloat(seconds, 64)
+		if err != nil || !(s >= 0 && s < 60) {
+			return 0, invalid
+		}
+		at, ok := secondsDuration(float64(m)*60 + s)
+		if !ok {
+			return 0, invalid
+		}
+		return at, nil
+	}
+	at, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, invalid
+	}
+	return at, nil
+}
+
+// secondsDuration converts seconds to a duration, ok is false for NaN, infinities and values out of its range.
+func secondsDuration(seconds float64) (time.Duration, bool) {
+	if math.IsNaN(seconds) || math.Abs(seconds) >= float64(math.MaxInt64)/float64(time.Second) {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// @Summary 	Add song translation
+// @Description	Add or replace the text of the song in a language. The language is detected from the text when not given.
+// @Description	With original the version becomes the original text of the song, the previous original is kept as a translation
+// @Tags 		songs
+// @Accept 		json
+// @Produce 	json
+// @Param 		song_id 	path 		int 						true 	"Song ID"
+// @Param 		body 		body 		model.LyricsVersionRequest 	true 	"Translation"
+// @Success 	201 		{object} 	model.LyricsVersion
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	409 		{object} 	model.Problem 	"The language is the original language of the song or the previous original cannot be kept"
+// @Failure 	422 		{object} 	model.Problem 	"Invalid language or the language cannot be detected"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to add song translation"
+// @Router 		/api/v1/songs/{song_id}/translations [post]
+func (ro *Router) addLyricsVersion(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to add song translation: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	var req model.LyricsVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log().Error(r.Context(), "Failed to add song translation: invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	version, err := ro.songService.AddLyricsVersion(r.Context(), int(song_id), req)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to add song translation: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Lyrics of song %d in %s were added", version.SongID, version.Lang)
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/songs/%d/translations/%s", version.SongID, version.Lang))
+	JSONResponse(r.Context(), w, http.StatusCreated, version)
+}
+
+// @Summary 	List song translations
+// @Description	Get the original text of the song and its translations, the original goes first
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Success 	200 		{array} 	model.LyricsVersion
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to list song translations"
+// @Router 		/api/v1/songs/{song_id}/translations [get]
+func (ro *Router) listLyricsVersions(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to list song translations: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	versions, err := ro.songService.ListLyricsVersions(r.Context(), int(song_id))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to list song translations: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "List song translations")
+	JSONResponse(r.Context(), w, http.StatusOK, versions)
+}
+
+// @Summary 	Get song translation
+// @Description	Get the text of the song in a language, the original one included
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 		true 	"Song ID"
+// @Param 		lang 		path 		string 		true 	"ISO 639 code of the language"
+// @Success 	200 		{object} 	model.LyricsVersion
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song or its text in the language not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get song translation"
+// @Router 		/api/v1/songs/{song_id}/translations/{lang} [get]
+func (ro *Router) getLyricsVersion(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song translation: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	version, err := ro.songService.GetLyricsVersion(r.Context(), int(song_id), mux.Vars(r)["lang"])
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song translation: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get song translation")
+	JSONResponse(r.Context(), w, http.StatusOK, version)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePlaybackOffset(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "83.5", want: 83500 * time.Millisecond},
+		{value: "0", want: 0},
+		{value: "1m23.5s", want: 83500 * time.Millisecond},
+		{value: "01:23.50", want: 83500 * time.Millisecond},
+		{value: "120:00", want: 2 * time.Hour},
+		{value: "", wantErr: true},
+		{value: "NaN", wantErr: true},
+		{value: "Inf", wantErr: true},
+		{value: "-Inf", wantErr: true},
+		{value: "1e300", wantErr: true},
+		{value: "01:NaN", wantErr: true},
+		{value: "01:60", wantErr: true},
+		{value: "4294967295:00", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parsePlaybackOffset(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePlaybackOffset(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePlaybackOffset(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date encoded as "YYYY-MM-DD" in JSON
+type Date struct {
+	time.Time
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(dateLayout))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return err
+	}
+	d.Time = parsed
+	return nil
+}
+
+// Album is a release of a group with an ordered tracklist
+// @Description 	Represents a music album entity
+// @property 		ID 			The album ID
+// @property 		GroupID 	The group ID
+// @property 		Group 		The group name
+// @property 		Title 		The title of the album
+// @property 		ReleaseDate	(Optional) 	The release date of the album
+// @property 		Tracks 		The songs of the album in track order
+type Album struct {
+	ID          int          `json:"id"`
+	GroupID     int          `json:"groupId"`
+	Group       string       `json:"group"`
+	Title       string       `json:"title"`
+	ReleaseDate *Date        `json:"releaseDate,omitempty" swaggertype:"string" format:"date"`
+	Tracks      []AlbumTrack `json:"tracks"`
+}
+
+// AlbumTrack is a song at some position of the album
+// @Description Song placed on the album
+// @property Position The track number starting from 1
+// @property SongID The song ID
+// @property Song The title of the song
+type AlbumTrack struct {
+	Position int    `json:"position"`
+	SongID   int    `json:"songId"`
+	Song     string `json:"song"`
+}
+
+// AlbumCommon represents the data to create an album
+// @Description Data of an album without tracklist
+// @property Group The group name
+// @property Title The title of the album
+// @property ReleaseDate (Optional) The release date of the album
+type AlbumCommon struct {
+	Group       string `json:"group,omitempty"`
+	Title       string `json:"title,omitempty"`
+	ReleaseDate *Date  `json:"releaseDate,omitempty" swaggertype:"string" format:"date"`
+}
+
+// AlbumPatch is a JSON Merge Patch of an album
+// @Description Fields to change, absent fields are kept and null clears the field
+// @property Group (Optional) The group name, cannot be null
+// @property Title (Optional) The title of the album, cannot be null
+// @property ReleaseDate (Optional) The release date of the album
+type AlbumPatch struct {
+	Group       Nullable[string] `json:"group" swaggertype:"string"`
+	Title       Nullable[string] `json:"title" swaggertype:"string"`
+	ReleaseDate Nullable[Date]   `json:"releaseDate" swaggertype:"string" format:"date"`
+}
+
+// AlbumFilters defines the optional filtering criteria for albums
+// @Description Used for filtering albums by fields below
+// @property GroupID The group ID
+// @property Group The group name
+// @property Title The title of the album
+// @property SongID The ID of a song on the album
+type AlbumFilters struct {
+	GroupID int    `json:"group_id,omitempty"`
+	Group   string `json:"group,omitempty"`
+	Title   string `json:"title,omitempty"`
+	SongID  int    `json:"song_id,omitempty"`
+}
+
+// AlbumTracks is the new tracklist of an album
+// @Description Song IDs in track order
+// @property SongIDs The song IDs, the first one becomes track 1
+type AlbumTracks struct {
+	SongIDs []int `json:"songIds"`
+}
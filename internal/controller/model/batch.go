@@ -0,0 +1,57 @@
+package model
+
+const (
+	EnrichSync  = "sync"
+	EnrichDefer = "defer"
+	EnrichNone  = "none"
+
+	ImportCreated = "created"
+	ImportFailed  = "failed"
+
+	// MaxImportSongs limits the number of songs in one import request.
+	MaxImportSongs = 10000
+)
+
+// SongImport is one song of a bulk import
+// @Description Song with optional details, missing details can be requested from the external API
+// @property Group The group name
+// @property Song The title of the song
+// @property Text (Optional) The text of the song
+// @property ReleaseDate (Optional) The release date of the song
+// @property Link (Optional) A link to video for the song
+type SongImport struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	Text        string `json:"text,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Link        string `json:"link,omitempty"`
+
+	// Sources are the providers of details filled during the import, see SongDetail.Sources.
+	Sources map[string]string `json:"-"`
+	// NotFound is set when the details providers do not know the song, see EnrichmentNotFound.
+	NotFound bool `json:"-"`
+}
+
+// SongImportResult is the result of importing one song
+// @Description Result for the item with the same index in the request
+// @property Index The position of the item in the request, starting from 0
+// @property Status created or failed
+// @property ID (Optional) The ID of the created song
+// @property Error (Optional) Why the song was not created
+type SongImportResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SongImportReport is the result of a bulk import
+// @Description Counts of created and failed songs with a result for every item
+// @property Created The number of created songs
+// @property Failed The number of songs that were not created
+// @property Items Results in the order of the request
+type SongImportReport struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Items   []SongImportResult `json:"items"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// Details that can be requested again from the external API, the names match the fields of Song.
+const (
+	DetailText        = "text"
+	DetailReleaseDate = "releaseDate"
+	DetailLink        = "link"
+)
+
+// Details lists every model.Detail* in the order they are filled.
+var Details = []string{DetailText, DetailReleaseDate, DetailLink}
+
+// EnrichmentQueued is the result of a bulk re-enrichment
+// @Description 	Number of songs queued for enrichment
+// @property 		Queued 	Songs that got an enrichment job
+type EnrichmentQueued struct {
+	Queued int64 `json:"queued"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "strings"
+
+// Group is a music group (artist) that songs belong to
+// @Description 	Represents a music group entity
+// @property 		ID 			The group ID
+// @property 		Name 		The group name
+type Group struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GroupCommon represents the data required to create or rename a group
+// @Description Minimal required data to represent a group
+// @property Name The group name
+type GroupCommon struct {
+	Name string `json:"name"`
+}
+
+// GroupKey matches group names ignoring case and extra spaces, like the lower(name) unique index of groups.
+func GroupKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// SongKey matches a song by its group and title the same way as GroupKey.
+func SongKey(group, song string) string {
+	return GroupKey(group) + "\x00" + GroupKey(song)
+}
@@ -0,0 +1,123 @@
+package model
+
+// Section types of structured lyrics.
+const (
+	SectionIntro  = "intro"
+	SectionVerse  = "verse"
+	SectionChorus = "chorus"
+	SectionBridge = "bridge"
+	SectionOutro  = "outro"
+)
+
+// LyricsSection is a stanza of the lyrics
+// @Description 	A verse, chorus, bridge, intro or outro
+// @property 		Type 		One of intro, verse, chorus, bridge, outro
+// @property 		Label 		(Optional) 	The marker of the section in the text, like "Verse 2"
+// @property 		Lines 		(Optional) 	The lines, absent for collapsed repeats
+// @property 		Repeat 		(Optional) 	How many times the section is sung in a row when more than once, from markers like x2
+// @property 		RepeatOf 	(Optional) 	The index of the earlier section this one repeats
+type LyricsSection struct {
+	Type     string   `json:"type"`
+	Label    string   `json:"label,omitempty"`
+	Lines    []string `json:"lines,omitempty"`
+	Repeat   int      `json:"repeat,omitempty"`
+	RepeatOf *int     `json:"repeatOf,omitempty"`
+}
+
+// Lyrics is the text of a song split into sections
+// @Description 	Structured lyrics of a song
+// @property 		SongID 		The song ID
+// @property 		Sections 	The sections in the order they are sung
+type Lyrics struct {
+	SongID   int             `json:"songId"`
+	Sections []LyricsSection `json:"sections"`
+}
+
+// Export formats of synced lyrics.
+const (
+	LyricsLRC = "lrc"
+	LyricsVTT = "vtt"
+	LyricsSRT = "srt"
+)
+
+// SyncedWord is a word of enhanced LRC
+// @Description 	A word with the time it starts
+// @property 		StartMs 	When the word starts, in milliseconds from the beginning of the song
+// @property 		Text 		The word with the following spaces
+type SyncedWord struct {
+	StartMs int64  `json:"startMs"`
+	Text    string `json:"text"`
+}
+
+// SyncedLine is a line of time-synced lyrics
+// @Description 	A line with the time it is sung
+// @property 		StartMs 	When the line starts, in milliseconds from the beginning of the song
+// @property 		EndMs 		When the next line starts or the song ends, 0 when unknown
+// @property 		Text 		The text of the line
+// @property 		Words 		(Optional) 	The words with their times from enhanced LRC
+type SyncedLine struct {
+	StartMs int64        `json:"startMs"`
+	EndMs   int64        `json:"endMs,omitempty"`
+	Text    string       `json:"text"`
+	Words   []SyncedWord `json:"words,omitempty"`
+}
+
+// SyncedLyrics are time-synced lyrics of a song
+// @Description 	Lyrics with line timestamps, the LRC offset is already applied
+// @property 		SongID 		The song ID
+// @property 		Title 		(Optional) 	The [ti:] tag
+// @property 		Artist 		(Optional) 	The [ar:] tag
+// @property 		Album 		(Optional) 	The [al:] tag
+// @property 		LengthMs 	(Optional) 	The [length:] tag in milliseconds
+// @property 		Lines 		The lines ordered by time
+type SyncedLyrics struct {
+	SongID   int          `json:"songId"`
+	Title    string       `json:"title,omitempty"`
+	Artist   string       `json:"artist,omitempty"`
+	Album    string       `json:"album,omitempty"`
+	LengthMs int64        `json:"lengthMs,omitempty"`
+	Lines    []SyncedLine `json:"lines"`
+}
+
+// ActiveLine is the line sung at a playback offset
+// @Description 	The line active at the requested offset
+// @property 		AtMs 		The requested offset in milliseconds
+// @property 		Index 		The index of the line, -1 before the first line and after the end
+// @property 		Line 		(Optional) 	The active line, absent before the first line and after the end
+// @property 		Word 		(Optional) 	The active word of enhanced LRC
+// @property 		Next 		(Optional) 	The line after the active one
+// @property 		Ended 		(Optional) 	Whether the offset is after the end of the last line given by [length:]
+type ActiveLine struct {
+	AtMs  int64       `json:"atMs"`
+	Index int         `json:"index"`
+	Line  *SyncedLine `json:"line,omitempty"`
+	Word  *SyncedWord `json:"word,omitempty"`
+	Next  *SyncedLine `json:"next,omitempty"`
+	Ended bool        `json:"ended,omitempty"`
+}
+
+// LyricsVersion is the text of a song in one language
+// @Description 	The original text of the song or its translation
+// @property 		SongID 		The song ID
+// @property 		Lang 		ISO 639 code of the language
+// @property 		Original 	Whether this is the original text of the song, the text of the song itself
+// @property 		Detected 	(Optional) 	Whether the language was detected from the text
+// @property 		Text 		The text in this language
+type LyricsVersion struct {
+	SongID   int    `json:"songId"`
+	Lang     string `json:"lang"`
+	Original bool   `json:"original"`
+	Detected bool   `json:"detected,omitempty"`
+	Text     string `json:"text"`
+}
+
+// LyricsVersionRequest adds a language version of the song text
+// @Description 	A translation or the language of the original text
+// @property 		Lang 		(Optional) 	ISO 639 code of the language, detected from the text when absent
+// @property 		Text 		(Optional) 	The text, required for translations. For the original it replaces the text of the song
+// @property 		Original 	(Optional) 	Mark the version as the original text of the song
+type LyricsVersionRequest struct {
+	Lang     string `json:"lang,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Original bool   `json:"original,omitempty"`
+}
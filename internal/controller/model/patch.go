@@ -0,0 +1,30 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable is a field of a JSON Merge Patch (RFC 7396). Set is false when the field is absent,
+// Null is true when the field is explicitly null, otherwise Value holds the new value.
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Set || n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullableUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Nullable[string]
+	}{
+		{name: "absent", data: `{}`, want: Nullable[string]{}},
+		{name: "null", data: `{"text": null}`, want: Nullable[string]{Set: true, Null: true}},
+		{name: "value", data: `{"text": "la la"}`, want: Nullable[string]{Set: true, Value: "la la"}},
+		{name: "empty value", data: `{"text": ""}`, want: Nullable[string]{Set: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch SongPatch
+			if err := json.Unmarshal([]byte(tt.data), &patch); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if patch.Text != tt.want {
+				t.Errorf("Unmarshal() text = %+v, want %+v", patch.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullableUnmarshalInvalid(t *testing.T) {
+	var patch AlbumPatch
+	if err := json.Unmarshal([]byte(`{"releaseDate": "16.07.2006"}`), &patch); err == nil {
+		t.Errorf("Unmarshal() of an invalid date error = nil, want an error")
+	}
+	if err := json.Unmarshal([]byte(`{"title": 1}`), &patch); err == nil {
+		t.Errorf("Unmarshal() of a number into a string error = nil, want an error")
+	}
+}
+
+func TestNullableMarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Nullable[string]
+		want  string
+	}{
+		{name: "absent", value: Nullable[string]{}, want: `null`},
+		{name: "null", value: Nullable[string]{Set: true, Null: true}, want: `null`},
+		{name: "value", value: Nullable[string]{Set: true, Value: "la la"}, want: `"la la"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
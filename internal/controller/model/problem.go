@@ -0,0 +1,16 @@
+package model
+
+// Problem is an error response in RFC 7807 format
+// @Description 	Problem details of a failed request
+// @property 		Type 		URI reference identifying the problem type
+// @property 		Title 		Short summary of the problem type
+// @property 		Status 		HTTP status code
+// @property 		Detail 		(Optional) 	Explanation specific to this occurrence
+// @property 		Instance 	(Optional) 	The request path
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionRevert  = "revert"
+	RevisionPurge   = "purge"
+)
+
+// SongSnapshot is the state of a song stored in its history
+// @Description Song fields at the moment of a revision
+// @property GroupID The group ID
+// @property Group The group name
+// @property Song The title of the song
+// @property Text (Optional) The text of the song
+// @property ReleaseDate (Optional) The release date of the song
+// @property Link (Optional) A link to video for the song
+type SongSnapshot struct {
+	GroupID     int    `json:"groupId"`
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	Text        string `json:"text,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Link        string `json:"link,omitempty"`
+
+	Sources map[string]string `json:"sources,omitempty"`
+}
+
+// SongRevision is one change of a song
+// @Description Immutable record of a song change
+// @property Revision The revision number, starting from 1 for every song
+// @property Action One of create, update, delete, restore, revert, purge
+// @property Old (Optional) The song before the change, absent for create
+// @property New (Optional) The song after the change, absent for delete
+// @property Actor (Optional) Who made the change, taken from the X-Actor header
+// @property RequestID (Optional) The X-Request-ID of the change
+// @property CreatedAt When the change was made
+type SongRevision struct {
+	Revision  int           `json:"revision"`
+	Action    string        `json:"action"`
+	Old       *SongSnapshot `json:"old,omitempty"`
+	New       *SongSnapshot `json:"new,omitempty"`
+	Actor     string        `json:"actor,omitempty"`
+	RequestID string        `json:"requestId,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
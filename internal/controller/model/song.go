@@ -1,33 +1,144 @@
 package model
 
+import "time"
+
 // Song is a title and group with optional data
 // @Description 	Represents a music song entity
+// @property 		ID 			The song ID
+// @property 		GroupID 	The group ID
 // @property 		Group 		The group name
 // @property 		Song 		The title of the song
 // @property 		Text 		(Optional) 	The text of the song
 // @property 		ReleaseDate	(Optional) 	The release date of the song
 // @property 		Link 		(Optional) 	A link to video for the song
+// @property 		Score 		(Optional) 	Similarity to the filters in fuzzy mode, from 0 to 1
+// @property 		DeletedAt 	(Optional) 	When the song was moved to the trash
+// @property 		Version 	The version of the song, increased on every change and used as its ETag
+// @property 		EnrichmentStatus 	Whether details from the external API are pending, done, failed, skipped or not_found
+// @property 		Sources 	(Optional) 	The providers the text, releaseDate and link came from
 type Song struct {
-	Group       string `json:"group"`
-	Song        string `json:"song"`
-	Text        string `json:"text,omitempty"`
-	ReleaseDate string `json:"releaseDate,omitempty"`
-	Link        string `json:"link,omitempty"`
+	ID          int        `json:"id"`
+	GroupID     int        `json:"groupId"`
+	Group       string     `json:"group"`
+	Song        string     `json:"song"`
+	Text        string     `json:"text,omitempty"`
+	ReleaseDate string     `json:"releaseDate,omitempty"`
+	Link        string     `json:"link,omitempty"`
+	Score       float64    `json:"score,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	Version     int        `json:"version"`
+
+	EnrichmentStatus string            `json:"enrichmentStatus"`
+	Sources          map[string]string `json:"sources,omitempty"`
 }
 
+const (
+	EnrichmentPending = "pending"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+	// EnrichmentSkipped is set when the details were not requested, for example without the external API.
+	EnrichmentSkipped = "skipped"
+	// EnrichmentNotFound is set when the details providers answered that they do not know the song.
+	EnrichmentNotFound = "not_found"
+)
+
+const (
+	MatchExact = "exact"
+	MatchFuzzy = "fuzzy"
+)
+
 // SongFilters defines the optional filtering criteria for songs
 // @Description 	Used for filtering songs by fields below
+// @property 		GroupID 	The group ID
 // @property 		Group 		The group name
 // @property 		Song 		The title of the song
 // @property 		Text 		(Optional) 	The text of the song
 // @property 		ReleaseDate	(Optional) 	The release date of the song
 // @property 		Link 		(Optional) 	A link to video for the song
+// @property 		AlbumID 	(Optional) 	The album ID, songs are returned in track order
+// @property 		Match 		(Optional) 	How group and song are matched: exact (substring) or fuzzy (trigram similarity)
+// @property 		Threshold 	(Optional) 	Minimal similarity in fuzzy mode, nil uses the default
+// @property 		Sort 		(Optional) 	Sort keys in priority order
+// @property 		Cursor 		(Optional) 	Position after which the next page starts in cursor mode
 type SongFilters struct {
-	Group       string `json:"group,omitempty"`
-	Song        string `json:"song,omitempty"`
-	Text        string `json:"text,omitempty"`
-	ReleaseDate string `json:"release_date,omitempty"`
-	Link        string `json:"link,omitempty"`
+	GroupID     int       `json:"group_id,omitempty"`
+	Group       string    `json:"group,omitempty"`
+	Song        string    `json:"song,omitempty"`
+	Text        string    `json:"text,omitempty"`
+	ReleaseDate string    `json:"release_date,omitempty"`
+	Link        string    `json:"link,omitempty"`
+	AlbumID     int       `json:"album_id,omitempty"`
+	Match       string    `json:"match,omitempty"`
+	Threshold   *float64  `json:"threshold,omitempty"`
+	Sort        []SortKey `json:"sort,omitempty"`
+	Cursor      string    `json:"cursor,omitempty"`
+}
+
+// SongPatch is a JSON Merge Patch of a song
+// @Description Fields to change, absent fields are kept and null clears the field
+// @property Group (Optional) The group name, cannot be null
+// @property Song (Optional) The title of the song, cannot be null
+// @property Text (Optional) The text of the song
+// @property ReleaseDate (Optional) The release date of the song
+// @property Link (Optional) A link to video for the song
+type SongPatch struct {
+	Group       Nullable[string] `json:"group" swaggertype:"string"`
+	Song        Nullable[string] `json:"song" swaggertype:"string"`
+	Text        Nullable[string] `json:"text" swaggertype:"string"`
+	ReleaseDate Nullable[string] `json:"releaseDate" swaggertype:"string"`
+	Link        Nullable[string] `json:"link" swaggertype:"string"`
+}
+
+// SortKey is a field to sort songs by
+// @Description Sort key of the song listing
+// @property Field One of group, song, release_date, id
+// @property Desc Sort in descending order
+type SortKey struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// SongPage is a page of the song listing
+// @Description Songs of one page with the total number of matching songs
+// @property Items The songs of the page
+// @property Page The page number, absent in cursor mode
+// @property PageSize The maximal number of songs per page
+// @property Total The number of songs matching the filters
+// @property NextCursor (Optional) The cursor of the next page in cursor mode, absent on the last page
+type SongPage struct {
+	Items      []Song `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Units of song text pagination.
+const (
+	TextUnitVerse = "verse"
+	TextUnitLine  = "line"
+	TextUnitChars = "chars"
+)
+
+// TextPage is a page of the song text
+// @Description Verses, lines or lines fitting a character budget of one page with the size of the whole text
+// @property Items The verses or lines of the page
+// @property Unit One of verse, line, chars
+// @property Page The page number
+// @property PageSize The number of verses or lines per page, or the character budget of a page
+// @property TotalVerses The number of verses in the text
+// @property TotalLines The number of non-blank lines in the text
+// @property TotalPages The number of pages
+// @property HasNext Whether there is a page after this one
+type TextPage struct {
+	Items       []string `json:"items"`
+	Unit        string   `json:"unit"`
+	Page        int      `json:"page"`
+	PageSize    int      `json:"page_size"`
+	TotalVerses int      `json:"total_verses"`
+	TotalLines  int      `json:"total_lines"`
+	TotalPages  int      `json:"total_pages"`
+	HasNext     bool     `json:"has_next"`
 }
 
 // SongDetail represents details about song
@@ -39,6 +150,9 @@ type SongDetail struct {
 	ReleaseDate string `json:"releaseDate"`
 	Text        string `json:"text"`
 	Link        string `json:"link"`
+
+	// Sources maps the filled details (Detail*) to the providers they came from, it is not part of the API.
+	Sources map[string]string `json:"-"`
 }
 
 // SongCommon represents the common data for a song
@@ -49,3 +163,24 @@ type SongCommon struct {
 	Group string `json:"group"`
 	Song  string `json:"song"`
 }
+
+// SongSearchResult is a song found by full-text search
+// @Description Song matching the search query
+// @property ID The song ID
+// @property GroupID The group ID
+// @property Group The group name
+// @property Song The title of the song
+// @property ReleaseDate (Optional) The release date of the song
+// @property Link (Optional) A link to video for the song
+// @property Rank Relevance of the song to the query, higher is better
+// @property Headline Fragments of the lyrics with matches wrapped in <mark></mark>
+type SongSearchResult struct {
+	ID          int     `json:"id"`
+	GroupID     int     `json:"groupId"`
+	Group       string  `json:"group"`
+	Song        string  `json:"song"`
+	ReleaseDate string  `json:"releaseDate,omitempty"`
+	Link        string  `json:"link,omitempty"`
+	Rank        float64 `json:"rank"`
+	Headline    string  `json:"headline,omitempty"`
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+const (
+	_defaultPageSize = 10
+	_maxPageSize     = 100
+
+	// _defaultCharBudget and _maxCharBudget are page_size of the song text paged by characters.
+	_defaultCharBudget = 1000
+	_maxCharBudget     = 10000
+)
+
+// parsePagination reads page and page_size query parameters.
+// Both must be positive, page_size above the maximum is capped.
+func parsePagination(r *http.Request) (int, int, error) {
+	page, err := parsePage(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	pageSize, err := parsePageSize(r, _defaultPageSize, _maxPageSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	return page, pageSize, nil
+}
+
+// parseTextPagination reads unit, page and page_size of the song text.
+// With unit=chars page_size is the number of characters per page with its own default and maximum.
+func parseTextPagination(r *http.Request) (string, int, int, error) {
+	unit := r.URL.Query().Get("unit")
+	page, err := parsePage(r)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	defaultSize, maxSize := _defaultPageSize, _maxPageSize
+	if unit == model.TextUnitChars {
+		defaultSize, maxSize = _defaultCharBudget, _maxCharBudget
+	}
+	pageSize, err := parsePageSize(r, defaultSize, maxSize)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return unit, page, pageSize, nil
+}
+
+func parsePage(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("page")
+	if value == "" {
+		return 1, nil
+	}
+	page, err := strconv.Atoi(value)
+	if err != nil || page < 1 {
+		return 0, errors.New("invalid page provided")
+	}
+	return page, nil
+}
+
+func parsePageSize(r *http.Request, defaultSize, maxSize int) (int, error) {
+	value := r.URL.Query().Get("page_size")
+	if value == "" {
+		return defaultSize, nil
+	}
+	pageSize, err := strconv.Atoi(value)
+	if err != nil || pageSize < 1 {
+		return 0, errors.New("invalid page size provided")
+	}
+	return min(pageSize, maxSize), nil
+}
+
+// parseSort reads a comma separated list of sort keys like "group,-release_date" or "song:desc".
+func parseSort(value string) ([]model.SortKey, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var keys []model.SortKey
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		key := model.SortKey{Field: item}
+		if strings.HasPrefix(item, "-") {
+			key = model.SortKey{Field: item[1:], Desc: true}
+		} else if field, direction, found := strings.Cut(item, ":"); found {
+			switch strings.ToLower(direction) {
+			case "asc":
+				key = model.SortKey{Field: field}
+			case "desc":
+				key = model.SortKey{Field: field, Desc: true}
+			default:
+				return nil, errors.New("invalid sort direction " + direction)
+			}
+		}
+		if key.Field == "" {
+			return nil, errors.New("empty sort key")
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
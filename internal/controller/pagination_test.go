@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []model.SortKey
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: nil},
+		{name: "one key", value: "song", want: []model.SortKey{{Field: "song"}}},
+		{name: "minus is descending", value: "-release_date", want: []model.SortKey{{Field: "release_date", Desc: true}}},
+		{
+			name:  "several keys with directions",
+			value: "group, song:DESC,release_date:asc,-id",
+			want: []model.SortKey{
+				{Field: "group"},
+				{Field: "song", Desc: true},
+				{Field: "release_date"},
+				{Field: "id", Desc: true},
+			},
+		},
+		{name: "unknown field is left to the service", value: "rating", want: []model.SortKey{{Field: "rating"}}},
+		{name: "invalid direction", value: "song:up", wantErr: true},
+		{name: "empty key", value: "song,,id", wantErr: true},
+		{name: "minus without field", value: "-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSort(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSort(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSort(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,22 +6,38 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
 	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/enrich"
+	"github.com/kleo-53/music-system/internal/middleware"
 	"github.com/kleo-53/music-system/pkg/logger"
 )
 
 type Router struct {
-	app         *mux.Router
-	songService core.SongService
+	app          *mux.Router
+	songService  core.SongService
+	groupService core.GroupService
+	albumService core.AlbumService
+	adminToken   string
+	enricher     enrich.DetailsProvider
 }
 
 func NewRouter(
 	app *mux.Router,
 	songService core.SongService,
+	groupService core.GroupService,
+	albumService core.AlbumService,
+	adminToken string,
+	enricher enrich.DetailsProvider,
 ) *Router {
 	router := &Router{
-		app:         app,
-		songService: songService,
+		app:          app,
+		songService:  songService,
+		groupService: groupService,
+		albumService: albumService,
+		adminToken:   adminToken,
+		enricher:     enricher,
 	}
 	router.initRoutes()
 	return router
@@ -34,12 +50,45 @@ func NewRouter(
 func (r *Router) initRoutes() {
 
 	s := r.app.PathPrefix("/api/v1").Subrouter()
+	s.Use(middleware.RequestID, middleware.Actor, r.cacheBypass)
 
-	s.HandleFunc("/songs", r.getSongsInfo).Methods("GET")         // Получение данных библиотеки с фильтрацией по всем полям и пагинацией
-	s.HandleFunc("/songs" , r.addSong).Methods("POST")           // Добавление новой песни в формате	JSON
-	s.HandleFunc("/songs/{song_id}", r.getSongText).Methods("GET") // Получение текста песни с пагинацией по куплетам
-	s.HandleFunc("/songs/{song_id}", r.updateSong).Methods("PATCH")         // Изменение данных песни
-	s.HandleFunc("/songs/{song_id}", r.deleteSong).Methods("DELETE")        // Удаление песни
+	s.HandleFunc("/songs", r.getSongsInfo).Methods("GET")                                             // Получение данных библиотеки с фильтрацией по всем полям и пагинацией
+	s.HandleFunc("/songs", r.addSong).Methods("POST")                                                 // Добавление новой песни в формате	JSON
+	s.HandleFunc("/songs:batch", r.importSongs).Methods("POST")                                       // Массовое добавление песен из JSON-массива или NDJSON
+	s.Handle("/songs:enrich", r.requireAdmin(r.enrichSongs)).Methods("POST")                          // Постановка в очередь обогащения всех песен без указанных деталей
+	s.HandleFunc("/songs/export", r.exportSongs).Methods("GET")                                       // Выгрузка всех подходящих под фильтры песен в CSV, NDJSON или JSON
+	s.HandleFunc("/songs/trash", r.getTrash).Methods("GET")                                           // Получение списка удалённых песен
+	s.Handle("/songs/trash", r.requireAdmin(r.purgeTrash)).Methods("DELETE")                          // Окончательное удаление песен, пролежавших в корзине дольше срока хранения
+	s.HandleFunc("/songs/{song_id}", r.getSong).Methods("GET")                                        // Получение всех данных песни
+	s.HandleFunc("/songs/{song_id}/text", r.getSongText).Methods("GET")                               // Получение текста песни с пагинацией по куплетам
+	s.HandleFunc("/songs/{song_id}/lyrics", r.getLyrics).Methods("GET")                               // Получение текста песни по секциям: куплеты, припевы, бриджи
+	s.HandleFunc("/songs/{song_id}/lyrics.lrc", r.putSyncedLyrics).Methods("PUT")                     // Загрузка синхронизированного текста в формате LRC
+	s.HandleFunc("/songs/{song_id}/lyrics.{format:lrc|vtt|srt}", r.exportSyncedLyrics).Methods("GET") // Выгрузка синхронизированного текста в LRC, WebVTT или SRT
+	s.HandleFunc("/songs/{song_id}/lyrics/synced", r.getSyncedLyrics).Methods("GET")                  // Получение синхронизированного текста по строкам
+	s.HandleFunc("/songs/{song_id}/lyrics/active", r.getActiveLine).Methods("GET")                    // Строка, звучащая в указанный момент воспроизведения
+	s.HandleFunc("/songs/{song_id}/translations", r.addLyricsVersion).Methods("POST")                 // Добавление перевода или указание языка оригинала
+	s.HandleFunc("/songs/{song_id}/translations", r.listLyricsVersions).Methods("GET")                // Оригинал и переводы текста песни
+	s.HandleFunc("/songs/{song_id}/translations/{lang}", r.getLyricsVersion).Methods("GET")           // Текст песни на указанном языке
+	s.HandleFunc("/songs/{song_id}", r.updateSong).Methods("PATCH")                                   // Изменение данных песни
+	s.HandleFunc("/songs/{song_id}", r.deleteSong).Methods("DELETE")                                  // Перемещение песни в корзину
+	s.HandleFunc("/songs/{song_id}/restore", r.restoreSong).Methods("POST")                           // Восстановление песни из корзины
+	s.HandleFunc("/songs/{song_id}/history", r.getSongHistory).Methods("GET")                         // История изменений песни
+	s.HandleFunc("/songs/{song_id}/revert/{revision}", r.revertSong).Methods("POST")                  // Откат песни к состоянию после указанной ревизии
+	s.HandleFunc("/songs/{song_id}/enrich", r.enrichSong).Methods("POST")                             // Повторный запрос деталей песни у внешнего API
+	s.HandleFunc("/search", r.searchSongs).Methods("GET")                                             // Полнотекстовый поиск по названиям, группам и текстам песен
+
+	s.HandleFunc("/groups", r.getGroups).Methods("GET")                 // Получение списка групп с фильтрацией по названию и пагинацией
+	s.HandleFunc("/groups", r.addGroup).Methods("POST")                 // Добавление новой группы
+	s.HandleFunc("/groups/{group_id}", r.getGroup).Methods("GET")       // Получение данных группы
+	s.HandleFunc("/groups/{group_id}", r.updateGroup).Methods("PATCH")  // Переименование группы
+	s.HandleFunc("/groups/{group_id}", r.deleteGroup).Methods("DELETE") // Удаление группы
+
+	s.HandleFunc("/albums", r.getAlbums).Methods("GET")                        // Получение списка альбомов с фильтрацией и пагинацией
+	s.HandleFunc("/albums", r.addAlbum).Methods("POST")                        // Добавление нового альбома
+	s.HandleFunc("/albums/{album_id}", r.getAlbum).Methods("GET")              // Получение альбома со списком треков
+	s.HandleFunc("/albums/{album_id}", r.updateAlbum).Methods("PATCH")         // Изменение данных альбома
+	s.HandleFunc("/albums/{album_id}", r.deleteAlbum).Methods("DELETE")        // Удаление альбома
+	s.HandleFunc("/albums/{album_id}/tracks", r.setAlbumTracks).Methods("PUT") // Замена списка треков альбома
 
 }
 
@@ -57,6 +106,37 @@ func JSONResponse(ctx context.Context, w http.ResponseWriter, status int, payloa
 	}
 }
 
-func JSONError(ctx context.Context, w http.ResponseWriter, status int, message string) {
-	JSONResponse(ctx, w, status, map[string]string{"error": message})
+// JSONProblem writes an RFC 7807 problem details body.
+func JSONProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	problem := model.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logger.Log().Error(r.Context(), "Failed to encode response: "+err.Error())
+	}
+}
+
+// ErrorResponse translates domain errors returned by services to HTTP statuses.
+// Internal errors are reported without details.
+func ErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch apperror.KindOf(err) {
+	case apperror.KindNotFound:
+		JSONProblem(w, r, http.StatusNotFound, apperror.MessageOf(err))
+	case apperror.KindConflict:
+		JSONProblem(w, r, http.StatusConflict, apperror.MessageOf(err))
+	case apperror.KindValidation:
+		JSONProblem(w, r, http.StatusUnprocessableEntity, apperror.MessageOf(err))
+	case apperror.KindUpstream:
+		JSONProblem(w, r, http.StatusBadGateway, apperror.MessageOf(err))
+	case apperror.KindPrecondition:
+		JSONProblem(w, r, http.StatusPreconditionFailed, apperror.MessageOf(err))
+	default:
+		JSONProblem(w, r, http.StatusInternalServerError, "Internal server error")
+	}
 }
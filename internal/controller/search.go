@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+// @Summary		Search songs
+// @Description	Full-text search over song titles, group names and lyrics ordered by relevance.
+// @Description	Words are combined with AND, "quoted words" match a phrase, word* matches a prefix and -word excludes a word.
+// @Tags		songs
+// @Produce		json
+// @Param		q			query		string		true	"Search query"
+// @Param		page		query		int			false	"Page number" 				default(1)
+// @Param		page_size	query		int 		false 	"Number of songs per page, at most 100" 	default(10)
+// @Success		200			{object} 	[]model.SongSearchResult
+// @Failure		400			{object} 	model.Problem 	"Invalid request payload"
+// @Failure		422			{object} 	model.Problem 	"Search query has no words"
+// @Failure		500			{object} 	model.Problem 	"Failed to search songs"
+// @Router		/api/v1/search [get]
+func (ro *Router) searchSongs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		logger.Log().Error(r.Context(), "Failed to search songs: no query provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to search songs: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	var songs []model.SongSearchResult
+	songs, err = ro.songService.SearchSongs(r.Context(), query, page, pageSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to search songs: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Search songs")
+	JSONResponse(r.Context(), w, http.StatusOK, songs)
+}
@@ -1,15 +1,19 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
+	"github.com/kleo-53/music-system/internal/apperror"
 	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/enrich"
 	"github.com/kleo-53/music-system/pkg/logger"
 )
 
@@ -24,143 +28,224 @@ type SongFilters struct {
 // getSongsInfo godoc
 //
 // @Summary		Get songs info
-// @Description	Get info about all songs with pagination and optional filters
+// @Description	Get info about all songs with pagination and optional filters.
+// @Description	Cursor pagination is stable when songs are added or deleted between requests.
 // @Tags		songs
 // @Accept		json
 // @Produce		json
+// @Param		group_id		query		int			false  "Filter by group ID"
 // @Param		group			query		string		false  "Filter by group name"
 // @Param		song			query		string		false  "Filter by song name"
-// @Param		text			query		string		false  "Filter by text content"
+// @Param		text			query		string		false  "Filter by words in the text"
 // @Param		release_date	query		string		false  "Filter by release date"
 // @Param		link			query		string		false  "Filter by link"
-// @Param		page			query		int			false	"Page number" 				default(1)
-// @Param		page_size		query		int 		false 	"Number of songs per page" 	default(10)
-// @Success		200				{object} 	[]model.SongCommon
-// @Failure		400				{object} 	map[string]string 	"Invalid request payload"
-// @Failure		500				{object} 	map[string]string 	"Failed to get any songs data"
+// @Param		album_id		query		int			false  "Filter by album ID, songs are returned in track order"
+// @Param		match			query		string		false  "How group and song filters match: exact substring or fuzzy similarity ordered by score" Enums(exact, fuzzy) default(exact)
+// @Param		threshold		query		number		false  "Minimal similarity for fuzzy match, from 0 to 1"
+// @Param		sort			query		string		false  "Comma separated sort keys: group, song, release_date, id; prefix with - or add :desc for descending order"
+// @Param		page			query		int			false	"Page number, cannot be used with cursor" 	default(1)
+// @Param		cursor			query		string		false	"Switches to cursor pagination: empty for the first page, then next_cursor of the previous page"
+// @Param		page_size		query		int 		false 	"Number of songs per page, at most 100" 	default(10)
+// @Success		200				{object} 	model.SongPage
+// @Header		200				{integer} 	X-Total-Count 	"Number of songs matching the filters"
+// @Failure		400				{object} 	model.Problem 	"Invalid request payload"
+// @Failure		422				{object} 	model.Problem 	"Invalid match mode, threshold, sort key or cursor"
+// @Failure		500				{object} 	model.Problem 	"Failed to get any songs data"
 // @Router		/api/v1/songs [get]
 func (ro *Router) getSongsInfo(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseSongFilters(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any songs data: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any songs data: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if r.URL.Query().Has("cursor") {
+		if r.URL.Query().Has("page") {
+			logger.Log().Error(r.Context(), "Failed to get any songs data: both page and cursor provided")
+			JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		filters.Cursor = r.URL.Query().Get("cursor")
+		page = 0
+	}
+	songs, err := ro.songService.GetSongsInfo(r.Context(), filters, page, pageSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get any songs data: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	logger.Log().Info(r.Context(), "Get songs data")
+	w.Header().Set("X-Total-Count", strconv.FormatInt(songs.Total, 10))
+	JSONResponse(r.Context(), w, http.StatusOK, songs)
+}
+
+// parseSongFilters reads the filters and the sort order of the song listing from the query.
+func parseSongFilters(r *http.Request) (model.SongFilters, error) {
 	filters := model.SongFilters{
 		Group:       r.URL.Query().Get("group"),
 		Song:        r.URL.Query().Get("song"),
 		Text:        r.URL.Query().Get("text"),
 		ReleaseDate: r.URL.Query().Get("release_date"),
 		Link:        r.URL.Query().Get("link"),
+		Match:       r.URL.Query().Get("match"),
+	}
+	if threshold := r.URL.Query().Get("threshold"); threshold != "" {
+		threshold_float, err := strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			return model.SongFilters{}, errors.New("invalid threshold provided")
+		}
+		filters.Threshold = &threshold_float
 	}
-	page := r.URL.Query().Get("page")
-	if page == "" {
-		page = "1"
+	if groupID := r.URL.Query().Get("group_id"); groupID != "" {
+		group_id, err := strconv.ParseInt(groupID, 10, strconv.IntSize)
+		if err != nil {
+			return model.SongFilters{}, errors.New("invalid group id provided")
+		}
+		filters.GroupID = int(group_id)
 	}
-	page_int, err := strconv.ParseInt(page, 10, strconv.IntSize)
+	if albumID := r.URL.Query().Get("album_id"); albumID != "" {
+		album_id, err := strconv.ParseInt(albumID, 10, strconv.IntSize)
+		if err != nil {
+			return model.SongFilters{}, errors.New("invalid album id provided")
+		}
+		filters.AlbumID = int(album_id)
+	}
+	sort, err := parseSort(r.URL.Query().Get("sort"))
 	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to update song info: invalid page provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
-		return
+		return model.SongFilters{}, err
 	}
-	pageSize := r.URL.Query().Get("page_size")
-	if pageSize == "" {
-		pageSize = "10"
+	filters.Sort = sort
+	return filters, nil
+}
+
+// @Summary 	Get song
+// @Description	Get full song data by ID
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 		path 		int 				true 	"Song ID"
+// @Param 		If-None-Match 	header 		string 				false 	"ETag of a cached copy of the song"
+// @Success 	200 			{object} 	model.Song
+// @Header 		200 			{string} 	ETag 			"Version of the song"
+// @Success 	304 			"The song was not changed"
+// @Failure 	400 			{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 			{object} 	model.Problem 	"Song not found"
+// @Failure 	500 			{object} 	model.Problem 	"Failed to get song"
+// @Router 		/api/v1/songs/{song_id} [get]
+func (ro *Router) getSong(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	songID := vars["song_id"]
+	if songID == "" {
+		logger.Log().Error(r.Context(), "Failed to get song: no id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
 	}
-	page_size_int, err := strconv.ParseInt(pageSize, 10, strconv.IntSize)
+	song_id, err := strconv.ParseInt(songID, 10, strconv.IntSize)
 	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to update song info: invalid page size provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		logger.Log().Error(r.Context(), "Failed to get song: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-	songs, err := ro.songService.GetSongsInfo(r.Context(), filters, int(page_int), int(page_size_int))
+	song, err := ro.songService.GetSong(r.Context(), int(song_id))
 	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to get any songs data: "+err.Error())
-		JSONError(r.Context(), w, http.StatusInternalServerError, "Failed to get any songs data")
+		logger.Log().Error(r.Context(), "Failed to get song: "+err.Error())
+		ErrorResponse(w, r, err)
 		return
 	}
-
-	logger.Log().Info(r.Context(), "Get songs data")
-	JSONResponse(r.Context(), w, http.StatusCreated, songs)
+	setSongETag(w, song)
+	if notModified(r, song) {
+		logger.Log().Info(r.Context(), "Song %d was not modified", song.ID)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	logger.Log().Info(r.Context(), "Get song")
+	JSONResponse(r.Context(), w, http.StatusOK, song)
 }
 
 // @Summary 	Get song text
-// @Description	Get text of song by ID with pagination
+// @Description	Get text of song by ID with pagination by verses, lines or a character budget.
+// @Description	Verses are separated by blank lines, Windows line endings included.
+// @Description	Breaking change: the endpoint used to return a plain array of verses with 201 Created, it now returns a page object with 200 OK
 // @Tags 		songs
 // @Accept 		json
 // @Produce 	json
 // @Param 		song_id 	path 		int 				true 	"Song ID"
+// @Param 		unit 		query 		string 				false 	"Pagination unit" 			Enums(verse, line, chars) default(verse)
 // @Param 		page 		query 		int 				false 	"Page number" 				default(1)
-// @Param 		page_size 	query 		int 				false 	"Number of verses per page"	default(10)
-// @Success 	200 		{object} 	[]string
-// @Failure 	400 		{object} 	map[string]string 	"Invalid request payload"
-// @Failure 	500 		{object} 	map[string]string 	"Failed to get song text"
-// @Router 		/api/v1/songs/{song_id} [get]
+// @Param 		page_size 	query 		int 				false 	"Number of verses or lines per page, at most 100 (default 10), or of characters for unit=chars, at most 10000 (default 1000)"
+// @Param 		lang 		query 		string 				false 	"ISO 639 code of the translation, the original text by default"
+// @Success 	200 		{object} 	model.TextPage
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song or its text in the language not found"
+// @Failure 	422 		{object} 	model.Problem 	"Unknown unit"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get song text"
+// @Router 		/api/v1/songs/{song_id}/text [get]
 func (ro *Router) getSongText(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	songID := vars["song_id"]
 	if songID == "" {
 		logger.Log().Error(r.Context(), "Failed to get song text: no id provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 	song_id, err := strconv.ParseInt(songID, 10, strconv.IntSize)
 	if err != nil {
 		logger.Log().Error(r.Context(), "Failed to update song info: invalid id provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
-		return
-	}
-	page := r.URL.Query().Get("page")
-	if page == "" {
-		page = "1"
-	}
-	page_int, err := strconv.ParseInt(page, 10, strconv.IntSize)
-	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to update song info: invalid page provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-	pageSize := r.URL.Query().Get("page_size")
-	if pageSize == "" {
-		pageSize = "10"
-	}
-	page_size_int, err := strconv.ParseInt(pageSize, 10, strconv.IntSize)
+	unit, page, pageSize, err := parseTextPagination(r)
 	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to update song info: invalid page size provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		logger.Log().Error(r.Context(), "Failed to get song text: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-	text, err := ro.songService.GetSongText(r.Context(), int(song_id), int(page_int), int(page_size_int))
+	text, err := ro.songService.GetSongText(r.Context(), int(song_id), r.URL.Query().Get("lang"), unit, page, pageSize)
 	if err != nil {
 		logger.Log().Error(r.Context(), "Failed to get song text: "+err.Error())
-		JSONError(r.Context(), w, http.StatusInternalServerError, "Failed to get song text")
+		ErrorResponse(w, r, err)
 		return
 	}
 	logger.Log().Info(r.Context(), "Get song text")
-	JSONResponse(r.Context(), w, http.StatusCreated, text)
+	JSONResponse(r.Context(), w, http.StatusOK, text)
 }
 
 // @Summary 	Delete song
-// @Description	Delete a song by ID
+// @Description	Move a song to the trash by ID, it can be restored until the trash is purged
 // @Tags 		songs
 // @Produce 	json
-// @Param 		song_id path 		int 				true 				"Song ID"
-// @Success		200 	{object} 	map[string]string 	"Song was deleted"
-// @Failure 	400 	{object} 	map[string]string 	"Invalid request payload"
-// @Failure 	500 	{object} 	map[string]string 	"Failed to delete song"
+// @Param 		song_id 	path 		int 				true 				"Song ID"
+// @Param 		If-Match 	header 		string 				false 				"ETag the song must still have"
+// @Success		200 		{object} 	map[string]string 	"Song was deleted"
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	412 		{object} 	model.Problem 	"Song was changed"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to delete song"
 // @Router 		/api/v1/songs/{song_id} [delete]
 func (ro *Router) deleteSong(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	songID := vars["song_id"]
 	if songID == "" {
 		logger.Log().Error(r.Context(), "Failed to get song text: no id provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 	song_id, err := strconv.ParseInt(songID, 10, strconv.IntSize)
 	if err != nil {
 		logger.Log().Error(r.Context(), "Failed to update song info: invalid id provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-	err = ro.songService.DeleteSong(r.Context(), int(song_id))
+	err = ro.songService.DeleteSong(r.Context(), int(song_id), ifMatchVersions(r))
 	if err != nil {
 		logger.Log().Error(r.Context(), "Failed to delete song: "+err.Error())
-		JSONError(r.Context(), w, http.StatusInternalServerError, "Failed to delete song")
+		ErrorResponse(w, r, err)
 		return
 	}
 	logger.Log().Info(r.Context(), "Song deleted successfully")
@@ -168,107 +253,276 @@ func (ro *Router) deleteSong(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary 	Update song
-// @Description	Update song information by ID
+// @Description	Update song information by ID with a JSON Merge Patch (RFC 7396): absent fields are kept,
+// @Description	null clears text, releaseDate or link. All changes are applied atomically
 // @Tags 		songs
 // @Accept 		json
+// @Accept 		application/merge-patch+json
 // @Produce 	json
 // @Param 		song_id path 		int 				true 					"Song ID"
-// @Param 		body 	body 		model.SongFilters 	true 					"Updated song data"
-// @Success 	200 	{object} 	map[string]string 	"Song was updated"
-// @Failure 	400 	{object} 	map[string]string 	"Invalid request payload"
-// @Failure 	500 	{object} 	map[string]string 	"Failed to update song info"
+// @Param 		If-Match 	header 	string 				false 					"ETag the song must still have"
+// @Param 		body 	body 		model.SongPatch 	true 					"Changed song fields"
+// @Success 	200 	{object} 	model.Song
+// @Header 		200 	{string} 	ETag 			"New version of the song"
+// @Failure 	400 	{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 	{object} 	model.Problem 	"Song not found"
+// @Failure 	412 	{object} 	model.Problem 	"Song was changed"
+// @Failure 	415 	{object} 	model.Problem 	"Unsupported content type"
+// @Failure 	422 	{object} 	model.Problem 	"Invalid input"
+// @Failure 	500 	{object} 	model.Problem 	"Failed to update song info"
 // @Router 		/api/v1/songs/{song_id} [patch]
 func (ro *Router) updateSong(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	songID := vars["song_id"]
 	if songID == "" {
 		logger.Log().Error(r.Context(), "Failed to update song info: no id provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 	song_id, err := strconv.ParseInt(songID, 10, strconv.IntSize)
 	if err != nil {
 		logger.Log().Error(r.Context(), "Failed to update song info: invalid id provided")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid request payload")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-	var newSongData model.SongFilters
-	decoder := json.NewDecoder(r.Body)
-	err = decoder.Decode(&newSongData)
-	if err != nil {
+	if !isMergePatch(r.Header.Get("Content-Type")) {
+		logger.Log().Error(r.Context(), "Failed to update song info: unsupported content type")
+		JSONProblem(w, r, http.StatusUnsupportedMediaType, "Use application/merge-patch+json or application/json")
+		return
+	}
+	var patch model.SongPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
 		logger.Log().Error(r.Context(), "Failed to update song info: "+err.Error())
-		JSONError(r.Context(), w, http.StatusInternalServerError, "Failed to update song info")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
 	}
-	err = ro.songService.UpdateSong(r.Context(), int(song_id), newSongData)
+	song, err := ro.songService.UpdateSong(r.Context(), int(song_id), patch, ifMatchVersions(r))
 	if err != nil {
 		logger.Log().Error(r.Context(), "Failed to update song info: "+err.Error())
-		JSONError(r.Context(), w, http.StatusInternalServerError, "Failed to update song info")
+		ErrorResponse(w, r, err)
 		return
 	}
+	setSongETag(w, song)
 	logger.Log().Info(r.Context(), "Song updated successfully")
-	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Song was updated"})
+	JSONResponse(r.Context(), w, http.StatusOK, song)
+}
+
+// isMergePatch accepts the merge patch media type and plain JSON, a missing content type is treated as JSON.
+func isMergePatch(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/merge-patch+json" || mediaType == "application/json")
 }
 
 // @Summary 	Add song
-// @Description	Add a new song to the system
+// @Description	Add a new song to the system. The song is returned at once with the pending enrichment status,
+// @Description	its details are requested from the external API in the background
 // @Tags 		songs
 // @Accept 		json
 // @Produce 	json
 // @Param 		body 	body 		model.SongCommon 	true 				"New song data"
-// @Success 	200 	{object} 	map[string]string 	"Song was added"
-// @Failure 	400 	{object} 	map[string]string 	"Invalid request payload"
-// @Failure 	500 	{object} 	map[string]string 	"Failed to add song"
+// @Success 	201 	{object} 	model.Song
+// @Header 		201 	{string} 	Location 			"URL of the created song"
+// @Failure 	400 	{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	422 	{object} 	model.Problem 	"Invalid input"
+// @Failure 	500 	{object} 	model.Problem 	"Failed to add song"
 // @Router 		/api/v1/songs [post]
 func (ro *Router) addSong(w http.ResponseWriter, r *http.Request) {
 	var req model.SongCommon
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Log().Error(r.Context(), "Failed to add song: invalid request payload")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Failed to add song")
+		JSONProblem(w, r, http.StatusBadRequest, "Failed to add song")
 		return
 	}
-	if req.Group == "" || req.Song == "" {
+	if strings.TrimSpace(req.Group) == "" || req.Song == "" {
 		logger.Log().Error(r.Context(), "Failed to add song: invalid input")
-		JSONError(r.Context(), w, http.StatusBadRequest, "Invalid input")
+		ErrorResponse(w, r, apperror.Validation("group and song are required"))
 		return
 	}
-	details, err := getSongDetails(r, req.Group, req.Song)
+	song, err := ro.songService.CreateSong(r.Context(), req, model.SongDetail{})
 	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to get song details:"+err.Error())
-		details = model.SongDetail{}
-		// JSONError(r.Context(), w, http.StatusNotImplemented, "Failed to get song details")
-		// return
+		logger.Log().Error(r.Context(), "Failed to add song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
 	}
+	logger.Log().Info(r.Context(), "Song %d was added", song.ID)
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/songs/%d", song.ID))
+	setSongETag(w, song.ToModel())
+	JSONResponse(r.Context(), w, http.StatusCreated, song.ToModel())
+}
 
-	err = ro.songService.CreateSong(r.Context(), req, details)
+// getSongDetails asks the external API for details of the song. It fails with enrich.ErrNotFound for songs
+// unknown to the API, a router without the API client gets empty details.
+func (ro *Router) getSongDetails(ctx context.Context, group, song string) (model.SongDetail, error) {
+	if ro.enricher == nil {
+		return model.SongDetail{}, nil
+	}
+	details, err := ro.enricher.SongDetails(ctx, group, song)
+	switch {
+	case err == nil:
+		return details, nil
+	case errors.Is(err, enrich.ErrNotFound):
+		return model.SongDetail{}, err
+	case errors.Is(err, enrich.ErrCircuitOpen):
+		return model.SongDetail{}, apperror.Upstream(err, "external api is temporarily unavailable")
+	default:
+		return model.SongDetail{}, apperror.Upstream(err, "failed to get song details from external api")
+	}
+}
+
+// @Summary		Get trash
+// @Description	Get deleted songs, most recently deleted first
+// @Tags		songs
+// @Produce		json
+// @Param		page		query		int			false	"Page number" 				default(1)
+// @Param		page_size	query		int 		false 	"Number of songs per page, at most 100" default(10)
+// @Success		200			{object} 	model.SongPage
+// @Header		200			{integer} 	X-Total-Count 	"Number of songs in the trash"
+// @Failure		400			{object} 	model.Problem 	"Invalid request payload"
+// @Failure		500			{object} 	model.Problem 	"Failed to get trash"
+// @Router		/api/v1/songs/trash [get]
+func (ro *Router) getTrash(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := parsePagination(r)
 	if err != nil {
-		logger.Log().Error(r.Context(), "Failed to add song: "+err.Error())
-		JSONError(r.Context(), w, http.StatusInternalServerError, "Failed to add song")
+		logger.Log().Error(r.Context(), "Failed to get trash: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	songs, err := ro.songService.GetTrash(r.Context(), page, pageSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get trash: "+err.Error())
+		ErrorResponse(w, r, err)
 		return
 	}
-	logger.Log().Info(r.Context(), "Song was added")
-	JSONResponse(r.Context(), w, http.StatusOK, map[string]string{"message": "Song was added"})
+	logger.Log().Info(r.Context(), "Get trash")
+	w.Header().Set("X-Total-Count", strconv.FormatInt(songs.Total, 10))
+	JSONResponse(r.Context(), w, http.StatusOK, songs)
 }
 
-func getSongDetails(r *http.Request, group, song string) (model.SongDetail, error) {
-	if err := godotenv.Load("config.env"); err != nil {
-		logger.Log().Warn(r.Context(), "No .env file found, using environment variables")
+// @Summary 	Restore song
+// @Description	Restore a deleted song from the trash
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id path 		int 				true 				"Song ID"
+// @Success		200 	{object} 	model.Song
+// @Failure 	400 	{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 	{object} 	model.Problem 	"Song is not in the trash"
+// @Failure 	500 	{object} 	model.Problem 	"Failed to restore song"
+// @Router 		/api/v1/songs/{song_id}/restore [post]
+func (ro *Router) restoreSong(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to restore song: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
 	}
-	fmt.Printf("%s?group=%s&song=%s", os.Getenv("EXTERNAL_API_URL"), group, song)
-	url := fmt.Sprintf("%s?group=%s&song=%s", os.Getenv("EXTERNAL_API_URL"), group, song)
-	resp, err := http.Get(url)
+	if err := ro.songService.RestoreSong(r.Context(), int(song_id)); err != nil {
+		logger.Log().Error(r.Context(), "Failed to restore song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	song, err := ro.songService.GetSong(r.Context(), int(song_id))
 	if err != nil {
-		return model.SongDetail{}, err
+		logger.Log().Error(r.Context(), "Failed to restore song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
 	}
-	defer resp.Body.Close()
+	logger.Log().Info(r.Context(), "Song %d was restored", song.ID)
+	setSongETag(w, song)
+	JSONResponse(r.Context(), w, http.StatusOK, song)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return model.SongDetail{}, fmt.Errorf("bad request to exxternal api: %s", resp.Status)
+// @Summary 	Purge trash
+// @Description	Permanently delete songs that were in the trash longer than the configured retention
+// @Tags 		songs
+// @Produce 	json
+// @Param 		X-Admin-Token 	header 		string 				true 	"Admin token"
+// @Success		200 			{object} 	map[string]int64 	"Number of purged songs"
+// @Failure 	401 			{object} 	model.Problem 	"Invalid admin token"
+// @Failure 	403 			{object} 	model.Problem 	"Admin endpoints are disabled"
+// @Failure 	500 			{object} 	model.Problem 	"Failed to purge trash"
+// @Router 		/api/v1/songs/trash [delete]
+func (ro *Router) purgeTrash(w http.ResponseWriter, r *http.Request) {
+	purged, err := ro.songService.PurgeTrash(r.Context())
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to purge trash: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
 	}
+	logger.Log().Info(r.Context(), "Purged %d songs from the trash", purged)
+	JSONResponse(r.Context(), w, http.StatusOK, map[string]int64{"purged": purged})
+}
 
-	var details model.SongDetail
-	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
-		return model.SongDetail{}, err
+// @Summary 	Get song history
+// @Description	Get revisions of the song, newest first. The history is kept for deleted and purged songs
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 				true 	"Song ID"
+// @Param 		page 		query 		int 				false 	"Page number" 				default(1)
+// @Param 		page_size 	query 		int 				false 	"Number of revisions per page, at most 100"	default(10)
+// @Success 	200 		{object} 	[]model.SongRevision
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song not found"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to get song history"
+// @Router 		/api/v1/songs/{song_id}/history [get]
+func (ro *Router) getSongHistory(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song history: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song history: "+err.Error())
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	history, err := ro.songService.GetSongHistory(r.Context(), int(song_id), page, pageSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to get song history: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
 	}
+	logger.Log().Info(r.Context(), "Get song history")
+	JSONResponse(r.Context(), w, http.StatusOK, history)
+}
 
-	return details, nil
+// @Summary 	Revert song
+// @Description	Return the song to its state right after the given revision. The revert is recorded as a new revision
+// @Tags 		songs
+// @Produce 	json
+// @Param 		song_id 	path 		int 				true 	"Song ID"
+// @Param 		revision 	path 		int 				true 	"Revision number"
+// @Param 		X-Actor 	header 		string 				false 	"Who makes the change"
+// @Success 	200 		{object} 	model.Song
+// @Failure 	400 		{object} 	model.Problem 	"Invalid request payload"
+// @Failure 	404 		{object} 	model.Problem 	"Song or revision not found"
+// @Failure 	422 		{object} 	model.Problem 	"Revision cannot be reverted to"
+// @Failure 	500 		{object} 	model.Problem 	"Failed to revert song"
+// @Router 		/api/v1/songs/{song_id}/revert/{revision} [post]
+func (ro *Router) revertSong(w http.ResponseWriter, r *http.Request) {
+	song_id, err := strconv.ParseInt(mux.Vars(r)["song_id"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to revert song: invalid id provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	revision, err := strconv.ParseInt(mux.Vars(r)["revision"], 10, strconv.IntSize)
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to revert song: invalid revision provided")
+		JSONProblem(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	song, err := ro.songService.RevertSong(r.Context(), int(song_id), int(revision))
+	if err != nil {
+		logger.Log().Error(r.Context(), "Failed to revert song: "+err.Error())
+		ErrorResponse(w, r, err)
+		return
+	}
+	logger.Log().Info(r.Context(), "Song %d was reverted to revision %d", song.ID, revision)
+	setSongETag(w, song)
+	JSONResponse(r.Context(), w, http.StatusOK, song)
 }
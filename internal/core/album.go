@@ -0,0 +1,52 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+type (
+	Album struct {
+		ID          int          `gorm:"column:id;primaryKey"`
+		GroupID     int          `gorm:"column:group_id"`
+		Group       Group        `gorm:"foreignKey:GroupID"`
+		Title       string       `gorm:"column:title"`
+		ReleaseDate *time.Time   `gorm:"column:release_date;type:date"`
+		Tracks      []AlbumTrack `gorm:"foreignKey:AlbumID"`
+	}
+
+	AlbumTrack struct {
+		AlbumID  int  `gorm:"column:album_id;primaryKey"`
+		Position int  `gorm:"column:position;primaryKey"`
+		SongID   int  `gorm:"column:song_id"`
+		Song     Song `gorm:"foreignKey:SongID"`
+	}
+
+	AlbumStore interface {
+		CreateAlbum(ctx context.Context, groupID int, album model.AlbumCommon) (model.Album, error)
+		UpdateAlbum(ctx context.Context, id, groupID int, patch model.AlbumPatch) error
+		SetAlbumTracks(ctx context.Context, id int, songIDs []int) error
+		DeleteAlbum(ctx context.Context, id int) error
+		GetAlbum(ctx context.Context, id int) (model.Album, error)
+		GetAlbums(ctx context.Context, filters model.AlbumFilters, page, pageSize int) ([]model.Album, error)
+	}
+
+	AlbumService interface {
+		CreateAlbum(ctx context.Context, album model.AlbumCommon) (model.Album, error)
+		UpdateAlbum(ctx context.Context, id int, patch model.AlbumPatch) error
+		SetAlbumTracks(ctx context.Context, id int, songIDs []int) error
+		DeleteAlbum(ctx context.Context, id int) error
+		GetAlbum(ctx context.Context, id int) (model.Album, error)
+		GetAlbums(ctx context.Context, filters model.AlbumFilters, page, pageSize int) ([]model.Album, error)
+	}
+)
+
+func (Album) TableName() string {
+	return "albums"
+}
+
+func (AlbumTrack) TableName() string {
+	return "album_tracks"
+}
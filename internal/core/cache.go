@@ -0,0 +1,32 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// DetailsCacheEntry is a cached answer of a details provider for a song.
+	DetailsCacheEntry struct {
+		Provider    string    `gorm:"column:provider;primaryKey"`
+		SongKey     string    `gorm:"column:song_key;primaryKey"`
+		Text        string    `gorm:"column:song_text"`
+		ReleaseDate string    `gorm:"column:release_date"`
+		Link        string    `gorm:"column:link"`
+		NotFound    bool      `gorm:"column:not_found"`
+		ExpiresAt   time.Time `gorm:"column:expires_at"`
+	}
+
+	// DetailsCacheStore persists answers of details providers between restarts.
+	DetailsCacheStore interface {
+		// GetEntry returns the entry while it is not expired, ok is false otherwise.
+		GetEntry(ctx context.Context, provider, songKey string) (entry DetailsCacheEntry, ok bool, err error)
+		PutEntry(ctx context.Context, entry DetailsCacheEntry) error
+		// DeleteExpired removes entries expired before now.
+		DeleteExpired(ctx context.Context) (int64, error)
+	}
+)
+
+func (DetailsCacheEntry) TableName() string {
+	return "details_cache"
+}
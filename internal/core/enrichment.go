@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+const (
+	JobQueued = "queued"
+	JobFailed = "failed"
+)
+
+type (
+	// EnrichmentJob asks to fill the details of a song from the external API.
+	EnrichmentJob struct {
+		ID          int64      `gorm:"column:id;primaryKey"`
+		SongID      int        `gorm:"column:song_id"`
+		Status      string     `gorm:"column:status;default:queued"`
+		Attempts    int        `gorm:"column:attempts"`
+		RunAt       time.Time  `gorm:"column:run_at;default:now()"`
+		LockedUntil *time.Time `gorm:"column:locked_until"`
+		LastError   string     `gorm:"column:last_error"`
+		// Overwrite replaces details that are already filled instead of keeping them.
+		Overwrite bool `gorm:"column:overwrite"`
+		// Group and Song are the names of the song, they are filled by ClaimJob.
+		Group string `gorm:"column:group_name;->"`
+		Song  string `gorm:"column:song;->"`
+	}
+
+	// EnrichmentQueue is a queue of enrichment jobs shared by all instances of the service.
+	EnrichmentQueue interface {
+		// ClaimJob takes the next due job for lease, ok is false when there is none.
+		ClaimJob(ctx context.Context, lease time.Duration) (job EnrichmentJob, ok bool, err error)
+		// EnqueueMissing adds jobs for songs that miss any of the details (model.Detail*, all when empty)
+		// and were not enriched since enrichedBefore, the zero time means any songs. Songs that already
+		// wait for enrichment are skipped. A non-zero enrichedBefore is the periodic refresh, it also leaves out
+		// songs imported without enrichment and songs the details providers do not know.
+		EnqueueMissing(ctx context.Context, details []string, overwrite bool, enrichedBefore time.Time) (int64, error)
+		// CompleteJob fills the details of the song, only empty ones unless the job overwrites, and removes the job.
+		CompleteJob(ctx context.Context, job EnrichmentJob, details model.SongDetail) error
+		// MarkNotFound removes the job of a song the details providers do not know and marks the song as not found.
+		MarkNotFound(ctx context.Context, job EnrichmentJob) error
+		// RetryJob returns the job to the queue to run again at runAt.
+		RetryJob(ctx context.Context, job EnrichmentJob, runAt time.Time, reason string) error
+		// FailJob keeps the job as failed and marks the enrichment of the song as failed.
+		FailJob(ctx context.Context, job EnrichmentJob, reason string) error
+	}
+)
+
+func (EnrichmentJob) TableName() string {
+	return "enrichment_jobs"
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"context"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+type (
+	Group struct {
+		ID   int    `gorm:"column:id;primaryKey"`
+		Name string `gorm:"column:name"`
+	}
+
+	GroupStore interface {
+		CreateGroup(ctx context.Context, name string) (model.Group, error)
+		ResolveGroup(ctx context.Context, name string) (model.Group, error)
+		UpdateGroup(ctx context.Context, id int, name string) error
+		DeleteGroup(ctx context.Context, id int) error
+		GetGroup(ctx context.Context, id int) (model.Group, error)
+		GetGroups(ctx context.Context, name string, page, pageSize int) ([]model.Group, error)
+	}
+
+	GroupService interface {
+		CreateGroup(ctx context.Context, name string) (model.Group, error)
+		UpdateGroup(ctx context.Context, id int, name string) error
+		DeleteGroup(ctx context.Context, id int) error
+		GetGroup(ctx context.Context, id int) (model.Group, error)
+		GetGroups(ctx context.Context, name string, page, pageSize int) ([]model.Group, error)
+	}
+)
+
+func (Group) TableName() string {
+	return "groups"
+}
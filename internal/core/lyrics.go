@@ -0,0 +1,44 @@
+package core
+
+import (
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// SyncedLyrics are the time-synced lyrics of a song uploaded as LRC.
+type SyncedLyrics struct {
+	SongID    int                `gorm:"column:song_id;primaryKey"`
+	Source    string             `gorm:"column:source"`
+	Data      model.SyncedLyrics `gorm:"column:data;serializer:json"`
+	UpdatedAt time.Time          `gorm:"column:updated_at"`
+}
+
+func (SyncedLyrics) TableName() string {
+	return "song_synced_lyrics"
+}
+
+// SongLyrics is a language version of a song text. The original version has no text of its own,
+// it is Song.Text, and the store fills Text from the song when reading it.
+type SongLyrics struct {
+	SongID    int       `gorm:"column:song_id;primaryKey"`
+	Lang      string    `gorm:"column:lang;primaryKey"`
+	Original  bool      `gorm:"column:original"`
+	Text      string    `gorm:"column:text"`
+	Detected  bool      `gorm:"column:detected"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (SongLyrics) TableName() string {
+	return "song_lyrics"
+}
+
+func (l SongLyrics) ToModel() model.LyricsVersion {
+	return model.LyricsVersion{
+		SongID:   l.SongID,
+		Lang:     l.Lang,
+		Original: l.Original,
+		Detected: l.Detected,
+		Text:     l.Text,
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// SongRevision is an immutable history record, OldData and NewData hold model.SongSnapshot as JSON.
+type SongRevision struct {
+	ID        int64     `gorm:"column:id;primaryKey"`
+	SongID    int       `gorm:"column:song_id"`
+	Revision  int       `gorm:"column:revision"`
+	Action    string    `gorm:"column:action"`
+	OldData   *string   `gorm:"column:old_data;type:jsonb"`
+	NewData   *string   `gorm:"column:new_data;type:jsonb"`
+	Actor     string    `gorm:"column:actor"`
+	RequestID string    `gorm:"column:request_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (SongRevision) TableName() string {
+	return "song_revisions"
+}
+
+func (r SongRevision) ToModel() (model.SongRevision, error) {
+	revision := model.SongRevision{
+		Revision:  r.Revision,
+		Action:    r.Action,
+		Actor:     r.Actor,
+		RequestID: r.RequestID,
+		CreatedAt: r.CreatedAt,
+	}
+	var err error
+	if revision.Old, err = decodeSnapshot(r.OldData); err != nil {
+		return model.SongRevision{}, err
+	}
+	if revision.New, err = decodeSnapshot(r.NewData); err != nil {
+		return model.SongRevision{}, err
+	}
+	return revision, nil
+}
+
+// Snapshot returns the state of the song stored in the history.
+func (s Song) Snapshot() model.SongSnapshot {
+	return model.SongSnapshot{
+		GroupID:     s.GroupID,
+		Group:       s.Group.Name,
+		Song:        s.Song,
+		Text:        s.Text,
+		ReleaseDate: s.ReleaseDate,
+		Link:        s.Link,
+		Sources:     s.DetailSources,
+	}
+}
+
+func decodeSnapshot(data *string) (*model.SongSnapshot, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var snapshot model.SongSnapshot
+	if err := json.Unmarshal([]byte(*data), &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
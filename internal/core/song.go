@@ -2,37 +2,130 @@ package core
 
 import (
 	"context"
+	"time"
 
 	"github.com/kleo-53/music-system/internal/controller/model"
+	"gorm.io/gorm"
 )
 
 type (
 	Song struct {
-		ID          int    `gorm:"column:id;primaryKey"`
-		Group       string `gorm:"column:song_group"`
-		Song        string `gorm:"column:song"`
-		Text        string `gorm:"column:song_text"`
-		ReleaseDate string `gorm:"column:release_date"`
-		Link        string `gorm:"column:link"`
+		ID          int            `gorm:"column:id;primaryKey"`
+		GroupID     int            `gorm:"column:group_id"`
+		Group       Group          `gorm:"foreignKey:GroupID"`
+		Song        string         `gorm:"column:song"`
+		Text        string         `gorm:"column:song_text"`
+		ReleaseDate string         `gorm:"column:release_date"`
+		Link        string         `gorm:"column:link"`
+		DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at"`
+		// Version is increased by a trigger on every change, see migration 000008.
+		Version int `gorm:"column:version;->"`
+		// EnrichmentStatus is one of model.Enrichment*, pending songs have a job in EnrichmentQueue.
+		EnrichmentStatus string `gorm:"column:enrichment_status"`
+		// DetailSources maps model.Detail* to the provider that filled the detail.
+		DetailSources map[string]string `gorm:"column:detail_sources;serializer:json"`
+		// Lyrics are the sections of Text, the store keeps them in sync with it.
+		Lyrics []model.LyricsSection `gorm:"column:lyrics;serializer:json"`
+		// LyricsVersion is the lyrics.Version that parsed Lyrics.
+		LyricsVersion int `gorm:"column:lyrics_version"`
 	}
 
 	SongStore interface {
-		CreateSong(ctx context.Context, song model.SongCommon, details model.SongDetail) error
-		UpdateSong(ctx context.Context, id int, newData model.SongFilters) error
-		DeleteSong(ctx context.Context, id int) error
-		GetSongText(ctx context.Context, id, page, pageSize int) ([]string, error)
-		GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) ([]model.Song, error)
+		// CreateSong and CreateSongs add a job to EnrichmentQueue for songs with the pending enrichment status.
+		CreateSong(ctx context.Context, groupID int, song model.SongCommon, details model.SongDetail, status string) (Song, error)
+		// CreateSongs inserts songs in batches, errs[i] is the result for songs[i].
+		CreateSongs(ctx context.Context, songs []Song, batchSize int) (errs []error)
+		// UpdateSong and DeleteSong fail with apperror.ErrPrecondition unless versions is nil or contains the current version.
+		UpdateSong(ctx context.Context, id, groupID int, patch model.SongPatch, versions []int) (Song, error)
+		DeleteSong(ctx context.Context, id int, versions []int) error
+		RestoreSong(ctx context.Context, id int) error
+		PurgeSongs(ctx context.Context, deletedBefore time.Time) (int64, error)
+		GetTrash(ctx context.Context, page, pageSize int) (model.SongPage, error)
+		GetSong(ctx context.Context, id int) (model.Song, error)
+		// GetSongText returns the version of the text in lang, the text of the song itself when lang is empty.
+		GetSongText(ctx context.Context, id int, lang string) (string, error)
+		GetLyrics(ctx context.Context, id int) (model.Lyrics, error)
+		PutSyncedLyrics(ctx context.Context, id int, source string, lyrics model.SyncedLyrics) error
+		GetSyncedLyrics(ctx context.Context, id int) (SyncedLyrics, error)
+		// PutSongLyrics adds a language version of the song text, an original replaces the previous one.
+		PutSongLyrics(ctx context.Context, id int, version SongLyrics) (SongLyrics, error)
+		// ListSongLyrics returns the original first, with an empty Lang when its language is not stored.
+		ListSongLyrics(ctx context.Context, id int) ([]SongLyrics, error)
+		GetSongLyrics(ctx context.Context, id int, lang string) (SongLyrics, error)
+		// GetSongsInfo selects the page by offset when page > 0 and by filters.Cursor when page == 0.
+		GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) (model.SongPage, error)
+		// ExportSongs streams the songs matching the filters to fn without loading them all at once.
+		ExportSongs(ctx context.Context, filters model.SongFilters, fn func(model.Song) error) error
+		SearchSongs(ctx context.Context, tsQuery string, page, pageSize int) ([]model.SongSearchResult, error)
+		GetSongHistory(ctx context.Context, id, page, pageSize int) ([]model.SongRevision, error)
+		RevertSong(ctx context.Context, id, revision int) error
+		// FillSongDetails saves details received from the external API, filled details are replaced only with overwrite.
+		FillSongDetails(ctx context.Context, id int, details model.SongDetail, overwrite bool) (Song, error)
+		// MarkSongNotFound marks the song as unknown to the details providers and removes its job.
+		MarkSongNotFound(ctx context.Context, id int) (Song, error)
+		// EnqueueMissing is EnrichmentQueue.EnqueueMissing.
+		EnqueueMissing(ctx context.Context, details []string, overwrite bool, enrichedBefore time.Time) (int64, error)
 	}
 
 	SongService interface {
-		CreateSong(ctx context.Context, song model.SongCommon, details model.SongDetail) error
-		UpdateSong(ctx context.Context, id int, newData model.SongFilters) error
-		DeleteSong(ctx context.Context, id int) error
-		GetSongText(ctx context.Context, id, page, pageSize int) ([]string, error)
-		GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) ([]model.Song, error)
+		CreateSong(ctx context.Context, song model.SongCommon, details model.SongDetail) (Song, error)
+		// ImportSongs queues the enrichment of songs with missing details when enqueue is true,
+		// items listed in invalid failed to decode and are reported as failed.
+		ImportSongs(ctx context.Context, items []model.SongImport, invalid map[int]error, enqueue bool) (model.SongImportReport, error)
+		UpdateSong(ctx context.Context, id int, patch model.SongPatch, versions []int) (model.Song, error)
+		DeleteSong(ctx context.Context, id int, versions []int) error
+		RestoreSong(ctx context.Context, id int) error
+		PurgeTrash(ctx context.Context) (int64, error)
+		GetTrash(ctx context.Context, page, pageSize int) (model.SongPage, error)
+		GetSong(ctx context.Context, id int) (model.Song, error)
+		// GetSongText pages the text in lang, the original text when lang is empty, by model.TextUnit*.
+		GetSongText(ctx context.Context, id int, lang, unit string, page, pageSize int) (model.TextPage, error)
+		// GetLyrics returns the sections of the song text, collapse drops the lines of repeated sections.
+		GetLyrics(ctx context.Context, id int, collapse bool) (model.Lyrics, error)
+		// PutSyncedLyrics parses and stores LRC, GetSyncedLyrics returns the parsed lines.
+		PutSyncedLyrics(ctx context.Context, id int, lrc string) (model.SyncedLyrics, error)
+		GetSyncedLyrics(ctx context.Context, id int) (model.SyncedLyrics, error)
+		// ExportSyncedLyrics renders the synced lyrics as model.LyricsLRC, LyricsVTT or LyricsSRT.
+		ExportSyncedLyrics(ctx context.Context, id int, format string) (string, error)
+		// GetActiveLine returns the line sung at the playback offset.
+		GetActiveLine(ctx context.Context, id int, at time.Duration) (model.ActiveLine, error)
+		// AddLyricsVersion adds a translation or sets the original, the language is detected when not given.
+		AddLyricsVersion(ctx context.Context, id int, req model.LyricsVersionRequest) (model.LyricsVersion, error)
+		ListLyricsVersions(ctx context.Context, id int) ([]model.LyricsVersion, error)
+		GetLyricsVersion(ctx context.Context, id int, lang string) (model.LyricsVersion, error)
+		GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) (model.SongPage, error)
+		ExportSongs(ctx context.Context, filters model.SongFilters, fn func(model.Song) error) error
+		SearchSongs(ctx context.Context, query string, page, pageSize int) ([]model.SongSearchResult, error)
+		GetSongHistory(ctx context.Context, id, page, pageSize int) ([]model.SongRevision, error)
+		RevertSong(ctx context.Context, id, revision int) (model.Song, error)
+		EnrichSong(ctx context.Context, id int, details model.SongDetail, overwrite bool) (model.Song, error)
+		// MarkSongNotFound records that the details providers do not know the song.
+		MarkSongNotFound(ctx context.Context, id int) (model.Song, error)
+		// EnqueueEnrichment queues songs missing any of the details for the enrichment workers.
+		EnqueueEnrichment(ctx context.Context, missing []string, overwrite bool) (int64, error)
 	}
 )
 
 func (Song) TableName() string {
 	return "songs"
 }
+
+func (s Song) ToModel() model.Song {
+	song := model.Song{
+		ID:          s.ID,
+		GroupID:     s.GroupID,
+		Group:       s.Group.Name,
+		Song:        s.Song,
+		Text:        s.Text,
+		ReleaseDate: s.ReleaseDate,
+		Link:        s.Link,
+		Version:     s.Version,
+
+		EnrichmentStatus: s.EnrichmentStatus,
+		Sources:          s.DetailSources,
+	}
+	if s.DeletedAt.Valid {
+		song.DeletedAt = &s.DeletedAt.Time
+	}
+	return song
+}
@@ -0,0 +1,11 @@
+alter table songs add column if not exists song_group varchar;
+
+update songs
+set song_group = groups.name
+from groups
+where groups.id = songs.group_id;
+
+alter table songs alter column song_group set not null;
+alter table songs drop column if exists group_id;
+
+drop table if exists groups;
@@ -0,0 +1,25 @@
+create table if not exists groups(
+    id serial primary key,
+    name varchar not null
+);
+
+create unique index if not exists groups_name_lower_idx on groups (lower(name));
+
+insert into groups (name)
+select distinct on (lower(regexp_replace(trim(song_group), '\s+', ' ', 'g')))
+    regexp_replace(trim(song_group), '\s+', ' ', 'g')
+from songs
+order by lower(regexp_replace(trim(song_group), '\s+', ' ', 'g')), id
+on conflict do nothing;
+
+alter table songs add column if not exists group_id integer references groups(id) on delete restrict;
+
+update songs
+set group_id = groups.id
+from groups
+where lower(groups.name) = lower(regexp_replace(trim(songs.song_group), '\s+', ' ', 'g'));
+
+alter table songs alter column group_id set not null;
+alter table songs drop column if exists song_group;
+
+create index if not exists songs_group_id_idx on songs (group_id);
@@ -0,0 +1,18 @@
+create table if not exists albums(
+    id serial primary key,
+    group_id integer not null references groups(id) on delete restrict,
+    title varchar not null,
+    release_date date
+);
+
+create index if not exists albums_group_id_idx on albums (group_id);
+
+create table if not exists album_tracks(
+    album_id integer not null references albums(id) on delete cascade,
+    song_id integer not null references songs(id) on delete cascade,
+    position integer not null check (position > 0),
+    primary key (album_id, position),
+    unique (album_id, song_id)
+);
+
+create index if not exists album_tracks_song_id_idx on album_tracks (song_id);
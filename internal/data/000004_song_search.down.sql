@@ -0,0 +1,7 @@
+drop index if exists songs_text_vector_idx;
+drop trigger if exists groups_search_vector_trigger on groups;
+drop function if exists groups_search_vector_update();
+drop trigger if exists songs_search_vector_trigger on songs;
+drop function if exists songs_search_vector_update();
+drop index if exists songs_search_vector_idx;
+alter table songs drop column if exists search_vector;
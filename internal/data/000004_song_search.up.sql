@@ -0,0 +1,36 @@
+alter table songs add column if not exists search_vector tsvector;
+
+create or replace function songs_search_vector_update() returns trigger as $$
+begin
+    new.search_vector :=
+        setweight(to_tsvector('simple', coalesce(new.song, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce((select name from groups where id = new.group_id), '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(new.song_text, '')), 'C');
+    return new;
+end
+$$ language plpgsql;
+
+create trigger songs_search_vector_trigger
+    before insert or update of song, song_text, group_id on songs
+    for each row execute function songs_search_vector_update();
+
+-- Renaming a group has to refresh the vectors of its songs.
+create or replace function groups_search_vector_update() returns trigger as $$
+begin
+    update songs set group_id = group_id where group_id = new.id;
+    return new;
+end
+$$ language plpgsql;
+
+create trigger groups_search_vector_trigger
+    after update of name on groups
+    for each row when (old.name is distinct from new.name)
+    execute function groups_search_vector_update();
+
+update songs set group_id = group_id;
+
+create index if not exists songs_search_vector_idx on songs using gin (search_vector);
+
+-- The text filter of the song listing matches the lyrics alone, search_vector also covers titles and group names.
+create index if not exists songs_text_vector_idx on songs
+    using gin (to_tsvector('simple'::regconfig, coalesce(song_text, '')));
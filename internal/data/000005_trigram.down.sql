@@ -0,0 +1,2 @@
+drop index if exists groups_name_trgm_idx;
+drop index if exists songs_song_trgm_idx;
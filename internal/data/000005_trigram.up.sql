@@ -0,0 +1,4 @@
+create extension if not exists pg_trgm;
+
+create index if not exists songs_song_trgm_idx on songs using gin (song gin_trgm_ops);
+create index if not exists groups_name_trgm_idx on groups using gin (name gin_trgm_ops);
@@ -0,0 +1,4 @@
+delete from songs where deleted_at is not null;
+
+drop index if exists songs_deleted_at_idx;
+alter table songs drop column if exists deleted_at;
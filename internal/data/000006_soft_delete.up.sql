@@ -0,0 +1,3 @@
+alter table songs add column if not exists deleted_at timestamptz;
+
+create index if not exists songs_deleted_at_idx on songs (deleted_at);
@@ -0,0 +1,2 @@
+drop table if exists song_revisions;
+drop function if exists song_revisions_immutable();
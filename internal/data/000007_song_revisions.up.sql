@@ -0,0 +1,23 @@
+create table if not exists song_revisions (
+    id bigserial primary key,
+    -- No foreign key: the history outlives songs purged from the trash.
+    song_id integer not null,
+    revision integer not null,
+    action text not null check (action in ('create', 'update', 'delete', 'restore', 'revert', 'purge')),
+    old_data jsonb,
+    new_data jsonb,
+    actor text not null default '',
+    request_id text not null default '',
+    created_at timestamptz not null default now(),
+    unique (song_id, revision)
+);
+
+create or replace function song_revisions_immutable() returns trigger as $$
+begin
+    raise exception 'song revisions are immutable';
+end
+$$ language plpgsql;
+
+create trigger song_revisions_immutable_trigger
+    before update or delete on song_revisions
+    for each row execute function song_revisions_immutable();
@@ -0,0 +1,3 @@
+drop trigger if exists songs_version_trigger on songs;
+drop function if exists songs_version_update();
+alter table songs drop column if exists version;
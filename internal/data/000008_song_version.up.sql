@@ -0,0 +1,13 @@
+alter table songs add column if not exists version integer not null default 1;
+
+-- Every change of a song, including renames of its group, gives it a new version and ETag.
+create or replace function songs_version_update() returns trigger as $$
+begin
+    new.version := old.version + 1;
+    return new;
+end
+$$ language plpgsql;
+
+create trigger songs_version_trigger
+    before update on songs
+    for each row execute function songs_version_update();
@@ -0,0 +1,2 @@
+drop table if exists enrichment_jobs;
+alter table songs drop column if exists enrichment_status;
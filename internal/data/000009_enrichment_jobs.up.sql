@@ -0,0 +1,17 @@
+-- Songs added before the queue are considered enriched.
+alter table songs add column if not exists enrichment_status text not null default 'done'
+    check (enrichment_status in ('pending', 'done', 'failed', 'skipped', 'not_found'));
+
+create table if not exists enrichment_jobs (
+    id bigserial primary key,
+    song_id integer not null unique references songs (id) on delete cascade,
+    status text not null default 'queued' check (status in ('queued', 'failed')),
+    attempts integer not null default 0,
+    run_at timestamptz not null default now(),
+    -- A claimed job is hidden from other workers until the lease expires, so jobs of crashed workers are retried.
+    locked_until timestamptz,
+    last_error text not null default '',
+    created_at timestamptz not null default now()
+);
+
+create index if not exists enrichment_jobs_queued_idx on enrichment_jobs (run_at) where status = 'queued';
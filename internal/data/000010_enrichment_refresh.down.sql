@@ -0,0 +1,9 @@
+create or replace function songs_version_update() returns trigger as $$
+begin
+    new.version := old.version + 1;
+    return new;
+end
+$$ language plpgsql;
+
+alter table enrichment_jobs drop column if exists overwrite;
+alter table songs drop column if exists enriched_at;
@@ -0,0 +1,17 @@
+-- The time of the last answer of the external API, the scheduled refresh skips songs enriched recently.
+alter table songs add column if not exists enriched_at timestamptz;
+
+-- Jobs created by the re-enrich endpoints may replace details that are already filled.
+alter table enrichment_jobs add column if not exists overwrite boolean not null default false;
+
+-- Enrichment bookkeeping is not a change of the song: the refresh of enrichment_status and enriched_at
+-- by the workers must not give songs a new version, or clients get 412 on their next If-Match.
+create or replace function songs_version_update() returns trigger as $$
+begin
+    if (to_jsonb(new) - 'version' - 'enrichment_status' - 'enriched_at')
+        is distinct from (to_jsonb(old) - 'version' - 'enrichment_status' - 'enriched_at') then
+        new.version := old.version + 1;
+    end if;
+    return new;
+end
+$$ language plpgsql;
@@ -0,0 +1,3 @@
+-- Provenance of song details: maps text, releaseDate and link to the provider that filled them.
+-- Details entered by users have no source.
+alter table songs add column if not exists detail_sources jsonb;
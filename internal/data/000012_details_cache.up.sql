@@ -0,0 +1,15 @@
+-- Persistent cache of details providers answers, shared by all instances of the service.
+create table if not exists details_cache (
+    provider text not null,
+    -- Normalized "group\0song", see enrich.songKey.
+    song_key text not null,
+    song_text text not null default '',
+    release_date text not null default '',
+    link text not null default '',
+    -- The provider did not know the song (negative caching).
+    not_found boolean not null default false,
+    expires_at timestamptz not null,
+    primary key (provider, song_key)
+);
+
+create index if not exists details_cache_expires_at_idx on details_cache (expires_at);
@@ -0,0 +1,2 @@
+alter table songs drop column if exists lyrics_version;
+alter table songs drop column if exists lyrics;
@@ -0,0 +1,5 @@
+-- Sections of song_text parsed by the lyrics package, NULL for songs without text and songs
+-- added before the column, their text is parsed on read.
+alter table songs add column if not exists lyrics jsonb;
+-- The version of the parser that produced lyrics, sections of an older parser are parsed again on read.
+alter table songs add column if not exists lyrics_version integer not null default 0;
@@ -0,0 +1,8 @@
+create table if not exists song_synced_lyrics (
+    song_id integer primary key references songs (id) on delete cascade,
+    -- The LRC as uploaded, it is returned by the LRC export.
+    source text not null,
+    -- model.SyncedLyrics parsed from source with the offset applied.
+    data jsonb not null,
+    updated_at timestamptz not null default now()
+);
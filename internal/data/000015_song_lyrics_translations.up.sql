@@ -0,0 +1,15 @@
+create table if not exists song_lyrics (
+    song_id integer not null references songs (id) on delete cascade,
+    -- ISO 639 code of the language.
+    lang text not null,
+    -- The original version is songs.song_text, its text is NULL and only the language is stored.
+    original boolean not null default false,
+    text text,
+    -- The language was detected from the text rather than given by the client.
+    detected boolean not null default false,
+    updated_at timestamptz not null default now(),
+    primary key (song_id, lang),
+    check (original or text is not null)
+);
+
+create unique index if not exists song_lyrics_original_idx on song_lyrics (song_id) where original;
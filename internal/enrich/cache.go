@@ -0,0 +1,179 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+const (
+	_defaultCacheSize        = 1000
+	_defaultCacheTTL         = 24 * time.Hour
+	_defaultCacheNegativeTTL = time.Hour
+	_defaultCacheCleanup     = time.Hour
+)
+
+// Cache is a DetailsProvider remembering the answers of another provider in memory and optionally in
+// a persistent store. ErrNotFound is cached for a shorter time, other errors are never cached.
+type Cache struct {
+	provider DetailsProvider
+	memory   *lru
+	store    core.DetailsCacheStore
+
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	cleanupMu   sync.Mutex
+	lastCleanup time.Time
+}
+
+// CacheOption -.
+type CacheOption func(*Cache)
+
+// CacheSize sets how many answers are kept in memory.
+func CacheSize(size int) CacheOption {
+	return func(c *Cache) {
+		c.memory = newLRU(size)
+	}
+}
+
+// CacheTTL sets how long answers are kept.
+func CacheTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// CacheNegativeTTL sets how long songs unknown to the provider are remembered.
+func CacheNegativeTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.negativeTTL = ttl
+	}
+}
+
+// PersistentCache keeps the answers in store too, so they survive restarts and are shared by instances.
+func PersistentCache(store core.DetailsCacheStore) CacheOption {
+	return func(c *Cache) {
+		c.store = store
+	}
+}
+
+func NewCache(provider DetailsProvider, opts ...CacheOption) *Cache {
+	c := &Cache{
+		provider:    provider,
+		memory:      newLRU(_defaultCacheSize),
+		ttl:         _defaultCacheTTL,
+		negativeTTL: _defaultCacheNegativeTTL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type bypassCacheKey struct{}
+
+// WithoutCache makes the providers of the request ask their sources again, the fresh answers are still cached.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+func (c *Cache) Name() string {
+	return c.provider.Name()
+}
+
+func (c *Cache) SongDetails(ctx context.Context, group, song string) (model.SongDetail, error) {
+	key := model.SongKey(group, song)
+	if !cacheBypassed(ctx) {
+		if entry, ok := c.lookup(ctx, key); ok {
+			if entry.notFound {
+				return model.SongDetail{}, ErrNotFound
+			}
+			return entry.details, nil
+		}
+	}
+	details, err := c.provider.SongDetails(ctx, group, song)
+	switch {
+	case err == nil:
+		c.save(ctx, cacheEntry{key: key, details: details, expiresAt: time.Now().Add(c.ttl)})
+	case errors.Is(err, ErrNotFound):
+		c.save(ctx, cacheEntry{key: key, notFound: true, expiresAt: time.Now().Add(c.negativeTTL)})
+	}
+	return details, err
+}
+
+// lookup checks the memory first and then the persistent store, failures of the store are treated as misses.
+func (c *Cache) lookup(ctx context.Context, key string) (cacheEntry, bool) {
+	now := time.Now()
+	if entry, ok := c.memory.get(key, now); ok {
+		return entry, true
+	}
+	if c.store == nil {
+		return cacheEntry{}, false
+	}
+	stored, ok, err := c.store.GetEntry(ctx, c.Name(), key)
+	if err != nil {
+		logger.Log().Warn(ctx, "Failed to read details cache: %s", err.Error())
+		return cacheEntry{}, false
+	}
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := cacheEntry{
+		key: key,
+		details: model.SongDetail{
+			Text:        stored.Text,
+			ReleaseDate: stored.ReleaseDate,
+			Link:        stored.Link,
+		},
+		notFound:  stored.NotFound,
+		expiresAt: stored.ExpiresAt,
+	}
+	c.memory.put(entry)
+	return entry, true
+}
+
+func (c *Cache) save(ctx context.Context, entry cacheEntry) {
+	c.memory.put(entry)
+	if c.store == nil {
+		return
+	}
+	err := c.store.PutEntry(ctx, core.DetailsCacheEntry{
+		Provider:    c.Name(),
+		SongKey:     entry.key,
+		Text:        entry.details.Text,
+		ReleaseDate: entry.details.ReleaseDate,
+		Link:        entry.details.Link,
+		NotFound:    entry.notFound,
+		ExpiresAt:   entry.expiresAt,
+	})
+	if err != nil {
+		logger.Log().Warn(ctx, "Failed to write details cache: %s", err.Error())
+		return
+	}
+	c.cleanup(ctx)
+}
+
+// cleanup removes expired entries from the persistent store at most once per _defaultCacheCleanup.
+func (c *Cache) cleanup(ctx context.Context) {
+	c.cleanupMu.Lock()
+	if time.Since(c.lastCleanup) < _defaultCacheCleanup {
+		c.cleanupMu.Unlock()
+		return
+	}
+	c.lastCleanup = time.Now()
+	c.cleanupMu.Unlock()
+	if _, err := c.store.DeleteExpired(ctx); err != nil {
+		logger.Log().Warn(ctx, "Failed to remove expired details cache entries: %s", err.Error())
+	}
+}
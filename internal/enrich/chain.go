@@ -0,0 +1,119 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+const _defaultChainName = "chain"
+
+// Chain merges the details of several providers. Every detail is taken from the first provider in its
+// precedence that has a value, details without an explicit precedence use the order of the providers.
+type Chain struct {
+	providers  []DetailsProvider
+	precedence map[string][]DetailsProvider
+}
+
+// NewChain creates a chain of providers with unique names. precedence maps details (model.Detail*)
+// to the names of the providers asked for them, providers missing from the list are not asked for the detail.
+func NewChain(providers []DetailsProvider, precedence map[string][]string) (*Chain, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("enrich: chain needs at least one provider")
+	}
+	byName := make(map[string]DetailsProvider, len(providers))
+	for _, provider := range providers {
+		if _, ok := byName[provider.Name()]; ok {
+			return nil, fmt.Errorf("enrich: duplicate provider %q", provider.Name())
+		}
+		byName[provider.Name()] = provider
+	}
+	c := &Chain{
+		providers:  providers,
+		precedence: make(map[string][]DetailsProvider, len(model.Details)),
+	}
+	for _, name := range model.Details {
+		c.precedence[name] = providers
+	}
+	for name, order := range precedence {
+		if !slices.Contains(model.Details, name) {
+			return nil, fmt.Errorf("enrich: unknown detail %q, expected one of %s", name, strings.Join(model.Details, ", "))
+		}
+		var ordered []DetailsProvider
+		for _, providerName := range order {
+			provider, ok := byName[providerName]
+			if !ok {
+				return nil, fmt.Errorf("enrich: unknown provider %q in precedence of %s", providerName, name)
+			}
+			ordered = append(ordered, provider)
+		}
+		c.precedence[name] = ordered
+	}
+	// Only providers asked for some detail are queried.
+	c.providers = slices.DeleteFunc(slices.Clone(providers), func(provider DetailsProvider) bool {
+		for _, ordered := range c.precedence {
+			if slices.Contains(ordered, provider) {
+				return false
+			}
+		}
+		return true
+	})
+	return c, nil
+}
+
+func (c *Chain) Name() string {
+	return _defaultChainName
+}
+
+// providerResult is the answer of one provider of the chain.
+type providerResult struct {
+	details model.SongDetail
+	err     error
+}
+
+// SongDetails asks all providers at once and merges their answers, Sources of the result names the provider
+// of every filled detail. A failed provider fails the whole request when a detail could come from it,
+// so a lower precedence provider never replaces a temporarily unavailable one. ErrNotFound is returned
+// only when no provider knows the song.
+func (c *Chain) SongDetails(ctx context.Context, group, song string) (model.SongDetail, error) {
+	results := make(map[string]*providerResult, len(c.providers))
+	var wg sync.WaitGroup
+	for _, provider := range c.providers {
+		result := &providerResult{}
+		results[provider.Name()] = result
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result.details, result.err = provider.SongDetails(ctx, group, song)
+		}()
+	}
+	wg.Wait()
+
+	var merged model.SongDetail
+	found := false
+	for _, name := range model.Details {
+		for _, provider := range c.precedence[name] {
+			result := results[provider.Name()]
+			if errors.Is(result.err, ErrNotFound) {
+				continue
+			}
+			if result.err != nil {
+				return model.SongDetail{}, fmt.Errorf("%s: %w", provider.Name(), result.err)
+			}
+			found = true
+			if value := detail(result.details, name); value != "" {
+				setDetail(&merged, name, value, provider.Name())
+				break
+			}
+		}
+	}
+	if !found {
+		return model.SongDetail{}, ErrNotFound
+	}
+	return merged, nil
+}
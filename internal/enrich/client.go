@@ -0,0 +1,178 @@
+// Package enrich provides song details from the external API (GET /info?group=&song=), local reference files
+// and chains of them.
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+const (
+	_defaultTimeout          = 5 * time.Second
+	_defaultRetries          = 2
+	_defaultBackoffBase      = 200 * time.Millisecond
+	_defaultBackoffMax       = 2 * time.Second
+	_defaultBreakerThreshold = 5
+	_defaultBreakerCooldown  = 30 * time.Second
+
+	// maxErrorBody limits how much of an error response is kept in the error.
+	maxErrorBody = 512
+
+	_defaultClientName = "api"
+)
+
+// Client is the DetailsProvider of an HTTP API with the /info contract.
+type Client struct {
+	name    string
+	baseURL *url.URL
+	http    *http.Client
+	breaker *breaker
+
+	retries     int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+}
+
+// New creates a client of the API at baseURL, for example http://localhost:8080/info.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, errors.New("enrich: base url is required")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("enrich: invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("enrich: unsupported scheme %q", u.Scheme)
+	}
+	c := &Client{
+		name:        _defaultClientName,
+		baseURL:     u,
+		http:        &http.Client{Timeout: _defaultTimeout},
+		breaker:     newBreaker(_defaultBreakerThreshold, _defaultBreakerCooldown),
+		retries:     _defaultRetries,
+		backoffBase: _defaultBackoffBase,
+		backoffMax:  _defaultBackoffMax,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+func (c *Client) Name() string {
+	return c.name
+}
+
+// SongDetails returns the details of the song. It fails with ErrNotFound when the API does not know the song,
+// ErrCircuitOpen while the API keeps failing and *Error for other failures.
+func (c *Client) SongDetails(ctx context.Context, group, song string) (model.SongDetail, error) {
+	if !c.breaker.allow() {
+		return model.SongDetail{}, ErrCircuitOpen
+	}
+	var lastErr *Error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			if err := c.sleep(ctx, attempt); err != nil {
+				c.breaker.release()
+				return model.SongDetail{}, err
+			}
+		}
+		details, err := c.get(ctx, group, song)
+		if err == nil {
+			c.breaker.success()
+			return details, nil
+		}
+		if errors.Is(err, ErrNotFound) {
+			// The API works, it just has no data for the song.
+			c.breaker.success()
+			return model.SongDetail{}, err
+		}
+		var e *Error
+		if !errors.As(err, &e) {
+			c.breaker.release()
+			return model.SongDetail{}, err
+		}
+		e.Attempts = attempt + 1
+		lastErr = e
+		if !e.Retryable || ctx.Err() != nil {
+			break
+		}
+	}
+	switch {
+	case lastErr.Retryable:
+		c.breaker.failure()
+	case lastErr.StatusCode == http.StatusOK:
+		// The API answered 200 with a body that cannot be decoded, it is as broken as with a 5xx.
+		c.breaker.failure()
+	case lastErr.StatusCode != 0:
+		// The API responded, the request itself was wrong.
+		c.breaker.success()
+	default:
+		c.breaker.release()
+	}
+	return model.SongDetail{}, lastErr
+}
+
+func (c *Client) get(ctx context.Context, group, song string) (model.SongDetail, error) {
+	u := *c.baseURL
+	query := u.Query()
+	query.Set("group", group)
+	query.Set("song", song)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return model.SongDetail{}, &Error{Err: err}
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return model.SongDetail{}, &Error{Retryable: ctx.Err() == nil, Err: err}
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return model.SongDetail{}, ErrNotFound
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return model.SongDetail{}, &Error{
+			StatusCode: resp.StatusCode,
+			Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError,
+			Err:        fmt.Errorf("%s: %s", resp.Status, body),
+		}
+	}
+	var details model.SongDetail
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return model.SongDetail{}, &Error{StatusCode: resp.StatusCode, Err: fmt.Errorf("invalid response: %w", err)}
+	}
+	return details, nil
+}
+
+// sleep waits before the given retry, the delay is random between 0 and base * 2^(attempt-1) capped by max.
+func (c *Client) sleep(ctx context.Context, attempt int) error {
+	backoff := c.backoffBase << (attempt - 1)
+	if backoff > c.backoffMax || backoff <= 0 {
+		backoff = c.backoffMax
+	}
+	if backoff <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(rand.N(backoff))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
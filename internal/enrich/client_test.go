@@ -0,0 +1,102 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/infostub"
+)
+
+func newStubClient(t *testing.T, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := New(server.URL+"/info", append([]Option{Backoff(0, 0)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+func TestClientSongDetails(t *testing.T) {
+	stub := infostub.New([]infostub.Fixture{
+		{Group: "Muse", Song: "Supermassive Black Hole", ReleaseDate: "16.07.2006", Text: "Ooh baby", Link: "https://example.com"},
+		{Group: "Muse", Song: "Uprising", Status: http.StatusServiceUnavailable},
+		{Group: "Muse", Song: "Hysteria", Status: http.StatusForbidden},
+	})
+	client := newStubClient(t, stub.Handler(), Retries(1))
+
+	details, err := client.SongDetails(context.Background(), "  muse ", "supermassive  black hole")
+	if err != nil {
+		t.Fatalf("SongDetails() error = %v", err)
+	}
+	want := model.SongDetail{ReleaseDate: "16.07.2006", Text: "Ooh baby", Link: "https://example.com"}
+	if !reflect.DeepEqual(details, want) {
+		t.Errorf("SongDetails() = %+v, want %+v", details, want)
+	}
+
+	if _, err := client.SongDetails(context.Background(), "Muse", "Unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SongDetails() of an unknown song error = %v, want ErrNotFound", err)
+	}
+
+	var e *Error
+	_, err = client.SongDetails(context.Background(), "Muse", "Uprising")
+	if !errors.As(err, &e) || e.StatusCode != http.StatusServiceUnavailable || !e.Retryable || e.Attempts != 2 {
+		t.Errorf("SongDetails() of a failing song error = %#v, want retryable 503 after 2 attempts", err)
+	}
+	_, err = client.SongDetails(context.Background(), "Muse", "Hysteria")
+	if !errors.As(err, &e) || e.StatusCode != http.StatusForbidden || e.Retryable || e.Attempts != 1 {
+		t.Errorf("SongDetails() of a rejected song error = %#v, want 403 after 1 attempt", err)
+	}
+}
+
+func TestClientBreaker(t *testing.T) {
+	stub := infostub.New([]infostub.Fixture{
+		{Group: "Muse", Song: "Uprising", Status: http.StatusInternalServerError},
+	})
+	client := newStubClient(t, stub.Handler(), Retries(0), Breaker(2, time.Hour))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SongDetails(context.Background(), "Muse", "Uprising"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("SongDetails() #%d error = %v, want a request to the api", i+1, err)
+		}
+	}
+	if _, err := client.SongDetails(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("SongDetails() after failures error = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestClientBreakerInvalidResponse(t *testing.T) {
+	client := newStubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("<html>maintenance</html>"))
+	}), Retries(0), Breaker(2, time.Hour))
+
+	var e *Error
+	for i := 0; i < 2; i++ {
+		if _, err := client.SongDetails(context.Background(), "Muse", "Uprising"); !errors.As(err, &e) || e.StatusCode != http.StatusOK {
+			t.Fatalf("SongDetails() #%d error = %v, want an invalid response", i+1, err)
+		}
+	}
+	if _, err := client.SongDetails(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("SongDetails() after invalid responses error = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestClientNegativeRetries(t *testing.T) {
+	stub := infostub.New([]infostub.Fixture{
+		{Group: "Muse", Song: "Uprising", Status: http.StatusInternalServerError},
+	})
+	client := newStubClient(t, stub.Handler(), Retries(-1))
+
+	var e *Error
+	if _, err := client.SongDetails(context.Background(), "Muse", "Uprising"); !errors.As(err, &e) || e.Attempts != 1 {
+		t.Errorf("SongDetails() error = %v, want a failure after 1 attempt", err)
+	}
+}
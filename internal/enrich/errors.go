@@ -0,0 +1,34 @@
+package enrich
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when the provider does not know the song.
+	ErrNotFound = errors.New("song not found by details provider")
+	// ErrCircuitOpen is returned without calling the external API while it keeps failing.
+	ErrCircuitOpen = errors.New("external api circuit breaker is open")
+)
+
+// Error describes a failed request to the external API after all attempts.
+type Error struct {
+	// StatusCode is the HTTP status of the last response, 0 when no response was received.
+	StatusCode int
+	Attempts   int
+	// Retryable is true for network errors, timeouts, 429 and 5xx responses.
+	Retryable bool
+	Err       error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("external api responded with status %d after %d attempts: %v", e.StatusCode, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("external api request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
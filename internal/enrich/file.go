@@ -0,0 +1,123 @@
+package enrich
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// FileProvider serves song details from a local reference file loaded into memory.
+//
+// A .json file holds an array of objects with group, song, text, releaseDate and link, like the JSON export.
+// A .csv file has a header row with group, song and any of text, release_date (or releaseDate) and link,
+// so the CSV export can be used as is. Songs are matched by group and title ignoring case and extra spaces.
+type FileProvider struct {
+	name  string
+	songs map[string]model.SongDetail
+}
+
+// fileSong is a record of a JSON reference file.
+type fileSong struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	Text        string `json:"text"`
+	ReleaseDate string `json:"releaseDate"`
+	Link        string `json:"link"`
+}
+
+// NewFileProvider loads the reference file at path, the format is chosen by the extension.
+func NewFileProvider(name, path string) (*FileProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("enrich: %w", err)
+	}
+	defer f.Close()
+	var songs []fileSong
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&songs); err != nil {
+			return nil, fmt.Errorf("enrich: invalid reference file %s: %w", path, err)
+		}
+	case ".csv":
+		if songs, err = readCSV(f); err != nil {
+			return nil, fmt.Errorf("enrich: invalid reference file %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("enrich: unsupported reference file %s, expected .json or .csv", path)
+	}
+	p := &FileProvider{
+		name:  name,
+		songs: make(map[string]model.SongDetail, len(songs)),
+	}
+	for _, song := range songs {
+		p.songs[model.SongKey(song.Group, song.Song)] = model.SongDetail{
+			Text:        song.Text,
+			ReleaseDate: song.ReleaseDate,
+			Link:        song.Link,
+		}
+	}
+	return p, nil
+}
+
+func readCSV(r io.Reader) ([]fileSong, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		columns[strings.ReplaceAll(name, "_", "")] = i
+	}
+	if _, ok := columns["group"]; !ok {
+		return nil, errors.New("group column is required")
+	}
+	if _, ok := columns["song"]; !ok {
+		return nil, errors.New("song column is required")
+	}
+	var songs []fileSong
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return songs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		songs = append(songs, fileSong{
+			Group:       value("group"),
+			Song:        value("song"),
+			Text:        value("text"),
+			ReleaseDate: value("releasedate"),
+			Link:        value("link"),
+		})
+	}
+}
+
+func (p *FileProvider) Name() string {
+	return p.name
+}
+
+func (p *FileProvider) SongDetails(_ context.Context, group, song string) (model.SongDetail, error) {
+	details, ok := p.songs[model.SongKey(group, song)]
+	if !ok {
+		return model.SongDetail{}, ErrNotFound
+	}
+	return details, nil
+}
@@ -0,0 +1,66 @@
+package enrich
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// cacheEntry is a cached answer, notFound marks a cached ErrNotFound.
+type cacheEntry struct {
+	key       string
+	details   model.SongDetail
+	notFound  bool
+	expiresAt time.Time
+}
+
+// lru keeps up to size entries and evicts the least recently used one first.
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *lru) get(key string, now time.Time) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := element.Value.(cacheEntry)
+	if !now.Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return entry, true
+}
+
+func (c *lru) put(entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).key)
+	}
+}
@@ -0,0 +1,53 @@
+package enrich
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option -.
+type Option func(*Client)
+
+// Name sets the name of the client in chains and in the provenance of song details.
+func Name(name string) Option {
+	return func(c *Client) {
+		c.name = name
+	}
+}
+
+// Timeout limits a single attempt, retries get their own timeout.
+func Timeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.http.Timeout = timeout
+	}
+}
+
+// Retries sets how many times a failed request is repeated, 0 and negative values disable retries.
+func Retries(retries int) Option {
+	return func(c *Client) {
+		c.retries = max(retries, 0)
+	}
+}
+
+// Backoff sets the base and the maximal delay between retries. The delay doubles with every attempt
+// and a random part of it is used (full jitter), so clients do not retry in lockstep.
+func Backoff(base, max time.Duration) Option {
+	return func(c *Client) {
+		c.backoffBase = base
+		c.backoffMax = max
+	}
+}
+
+// Breaker opens the circuit after threshold consecutive failures and keeps it open for cooldown.
+func Breaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = newBreaker(threshold, cooldown)
+	}
+}
+
+// HTTPClient replaces the HTTP client, pass Timeout after it to change the timeout of the client.
+func HTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.http = client
+	}
+}
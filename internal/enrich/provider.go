@@ -0,0 +1,47 @@
+package enrich
+
+import (
+	"context"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// DetailsProvider is a source of song details.
+type DetailsProvider interface {
+	// Name identifies the provider in the configuration and in model.SongDetail.Sources.
+	Name() string
+	// SongDetails fails with ErrNotFound when the provider does not know the song,
+	// empty fields of the result mean that the provider has no such detail.
+	SongDetails(ctx context.Context, group, song string) (model.SongDetail, error)
+}
+
+// detail returns the value of the detail named by model.Detail*.
+func detail(d model.SongDetail, name string) string {
+	switch name {
+	case model.DetailText:
+		return d.Text
+	case model.DetailReleaseDate:
+		return d.ReleaseDate
+	case model.DetailLink:
+		return d.Link
+	}
+	return ""
+}
+
+// setDetail sets the detail named by model.Detail* and remembers its source.
+func setDetail(d *model.SongDetail, name, value, source string) {
+	switch name {
+	case model.DetailText:
+		d.Text = value
+	case model.DetailReleaseDate:
+		d.ReleaseDate = value
+	case model.DetailLink:
+		d.Link = value
+	default:
+		return
+	}
+	if d.Sources == nil {
+		d.Sources = map[string]string{}
+	}
+	d.Sources[name] = source
+}
@@ -0,0 +1,73 @@
+package infostub
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Fixture is the answer of the stub for one song. A fixture file holds one fixture or an array of them:
+//
+//	{"group": "Muse", "song": "Supermassive Black Hole", "releaseDate": "16.07.2006", "text": "...", "link": "..."}
+//
+// Status other than 0 and 200 makes the song always fail with it, Delay is added to the latency of the stub.
+type Fixture struct {
+	Group       string   `json:"group"`
+	Song        string   `json:"song"`
+	ReleaseDate string   `json:"releaseDate"`
+	Text        string   `json:"text"`
+	Link        string   `json:"link"`
+	Status      int      `json:"status,omitempty"`
+	Delay       Duration `json:"delay,omitempty"`
+}
+
+// Duration is a time.Duration written as "1.5s" in fixtures.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// LoadFixtures reads all .json files of the directory.
+func LoadFixtures(dir string) ([]Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var batch []Fixture
+		if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+			err = json.Unmarshal(data, &batch)
+		} else {
+			batch = make([]Fixture, 1)
+			err = json.Unmarshal(data, &batch[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+		}
+		for _, fixture := range batch {
+			if fixture.Group == "" || fixture.Song == "" {
+				return nil, fmt.Errorf("invalid fixture %s: group and song are required", path)
+			}
+		}
+		fixtures = append(fixtures, batch...)
+	}
+	return fixtures, nil
+}
@@ -0,0 +1,96 @@
+// Package infostub is a stub of the external song details API (GET /info?group=&song=) serving fixtures,
+// with configurable latency and failures to exercise the enrichment without network.
+package infostub
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+var _defaultErrorStatuses = []int{http.StatusInternalServerError}
+
+type Server struct {
+	fixtures map[string]Fixture
+
+	latency       time.Duration
+	jitter        time.Duration
+	errorRate     float64
+	errorStatuses []int
+}
+
+func New(fixtures []Fixture, opts ...Option) *Server {
+	s := &Server{
+		fixtures:      make(map[string]Fixture, len(fixtures)),
+		errorStatuses: _defaultErrorStatuses,
+	}
+	for _, fixture := range fixtures {
+		s.fixtures[model.SongKey(fixture.Group, fixture.Song)] = fixture
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handler serves GET /info.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", s.info)
+	return mux
+}
+
+func (s *Server) info(w http.ResponseWriter, r *http.Request) {
+	group, song := r.URL.Query().Get("group"), r.URL.Query().Get("song")
+	fixture, found := s.fixtures[model.SongKey(group, song)]
+	if !s.wait(r, time.Duration(fixture.Delay)) {
+		return
+	}
+	status := http.StatusOK
+	switch {
+	case group == "" || song == "":
+		status = http.StatusBadRequest
+	case s.errorRate > 0 && rand.Float64() < s.errorRate:
+		status = s.errorStatuses[rand.IntN(len(s.errorStatuses))]
+	case !found:
+		status = http.StatusNotFound
+	case fixture.Status != 0:
+		status = fixture.Status
+	}
+	logger.Log().Info(r.Context(), "GET /info group=%q song=%q: %d", group, song, status)
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(model.SongDetail{
+		ReleaseDate: fixture.ReleaseDate,
+		Text:        fixture.Text,
+		Link:        fixture.Link,
+	}); err != nil {
+		logger.Log().Error(r.Context(), "Failed to encode response: "+err.Error())
+	}
+}
+
+// wait sleeps for the latency of the answer, it is false when the client went away.
+func (s *Server) wait(r *http.Request, delay time.Duration) bool {
+	delay += s.latency
+	if s.jitter > 0 {
+		delay += rand.N(s.jitter)
+	}
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
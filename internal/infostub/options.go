@@ -0,0 +1,24 @@
+package infostub
+
+import "time"
+
+// Option -.
+type Option func(*Server)
+
+// Latency delays every answer by latency plus a random part of jitter.
+func Latency(latency, jitter time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+		s.jitter = jitter
+	}
+}
+
+// ErrorRate makes the given share of requests, from 0 to 1, fail with one of statuses chosen at random.
+func ErrorRate(rate float64, statuses ...int) Option {
+	return func(s *Server) {
+		s.errorRate = rate
+		if len(statuses) > 0 {
+			s.errorStatuses = statuses
+		}
+	}
+}
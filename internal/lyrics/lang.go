@@ -0,0 +1,141 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// languageCode matches ISO 639-1 codes and the three-letter ISO 639-2/3 ones.
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// Undetermined is the ISO 639-2 code of a text whose language is neither stored nor detected.
+const Undetermined = "und"
+
+// minDetectLetters is the number of letters below which the language is not guessed.
+const minDetectLetters = 10
+
+// scripts are the languages told apart by their alphabet alone. Latin and Cyrillic are handled separately.
+var scripts = []struct {
+	lang  string
+	table *unicode.RangeTable
+}{
+	{"ja", unicode.Hiragana},
+	{"ja", unicode.Katakana},
+	{"ko", unicode.Hangul},
+	{"zh", unicode.Han},
+	{"ar", unicode.Arabic},
+	{"he", unicode.Hebrew},
+	{"el", unicode.Greek},
+	{"hi", unicode.Devanagari},
+	{"th", unicode.Thai},
+	{"ka", unicode.Georgian},
+	{"hy", unicode.Armenian},
+}
+
+// cyrillicLetters are the Cyrillic languages that use a letter among Russian, Ukrainian and Belarusian.
+// A letter shared by two of them counts for both, the one with the most letters wins.
+var cyrillicLetters = map[rune][]string{
+	'ї': {"uk"}, 'є': {"uk"}, 'ґ': {"uk"},
+	'ў': {"be"},
+	'ъ': {"ru"},
+	'і': {"uk", "be"},
+	'и': {"ru", "uk"}, 'щ': {"ru", "uk"},
+	'ы': {"ru", "be"}, 'э': {"ru", "be"}, 'ё': {"ru", "be"},
+}
+
+// stopWords are the most frequent words of the Latin languages.
+var stopWords = map[string][]string{
+	"en": {"the", "and", "you", "to", "i", "it", "my", "me", "in", "of", "is", "on", "your", "that", "we", "be", "don't", "i'm", "all", "love"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "mi", "me", "tu", "te", "no", "yo", "por", "una", "con", "es", "amor", "las", "para"},
+	"fr": {"le", "la", "les", "de", "et", "je", "tu", "que", "ne", "pas", "un", "une", "est", "des", "mon", "moi", "toi", "dans", "qui", "c'est"},
+	"de": {"der", "die", "das", "und", "ich", "du", "nicht", "ist", "ein", "eine", "mich", "dich", "mir", "wir", "zu", "es", "mit", "auf", "sie", "den"},
+	"it": {"il", "la", "di", "che", "e", "non", "un", "una", "mi", "ti", "io", "tu", "per", "sono", "con", "del", "amore", "ma", "come", "nel"},
+	"pt": {"o", "a", "de", "que", "e", "não", "eu", "você", "um", "uma", "do", "da", "meu", "me", "te", "em", "com", "é", "amor", "pra"},
+}
+
+// IsLanguage reports whether the value looks like a lowercase ISO 639 language code.
+func IsLanguage(value string) bool {
+	return languageCode.MatchString(value)
+}
+
+// DetectLanguage guesses the ISO 639-1 language of the text by its alphabet and, for Latin texts,
+// by frequent words. The second result is false when the text is too short or the language is unknown.
+func DetectLanguage(text string) (string, bool) {
+	counts := map[string]int{}
+	letters, latin, cyrillic := 0, 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			for _, lang := range cyrillicLetters[unicode.ToLower(r)] {
+				counts[lang]++
+			}
+		default:
+			for _, script := range scripts {
+				if unicode.Is(script.table, r) {
+					counts[script.lang]++
+					break
+				}
+			}
+		}
+	}
+	if letters < minDetectLetters {
+		return "", false
+	}
+	// Kana marks Japanese even when most of the characters are kanji.
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > letters/2 {
+		return "ja", true
+	}
+	switch {
+	case cyrillic > letters/2:
+		best := "ru"
+		for _, lang := range []string{"uk", "be"} {
+			if counts[lang] > counts[best] {
+				best = lang
+			}
+		}
+		return best, true
+	case latin > letters/2:
+		return detectLatin(text)
+	}
+	best, bestCount := "", 0
+	for _, script := range scripts {
+		if count := counts[script.lang]; count > bestCount {
+			best, bestCount = script.lang, count
+		}
+	}
+	return best, bestCount > letters/2
+}
+
+// detectLatin picks the Latin language with the most frequent words in the text.
+func detectLatin(text string) (string, bool) {
+	words := map[string]int{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		words[strings.Trim(word, "'")]++
+	}
+	best, bestScore, second := "", 0, 0
+	for lang, list := range stopWords {
+		score := 0
+		for _, word := range list {
+			score += words[word]
+		}
+		if score > bestScore || score == bestScore && lang < best {
+			best, bestScore, second = lang, score, max(second, bestScore)
+		} else {
+			second = max(second, score)
+		}
+	}
+	if bestScore == 0 || bestScore == second {
+		return "", false
+	}
+	return best, true
+}
@@ -0,0 +1,59 @@
+package lyrics
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"russian", "Я помню чудное мгновенье, передо мной явилась ты", "ru", true},
+		{"russian without distinctive letters", "Мама мыла раму, а папа пел песню", "ru", true},
+		{"ukrainian", "Ще не вмерла України ні слава, ні воля", "uk", true},
+		{"belarusian", "Жывём і вучымся, бо ў нас ёсць мова і песня", "be", true},
+		{"belarusian without ў", "Купалінка, купалінка, цёмная ночка, дзе ж твая дочка", "be", true},
+		{"english", "Ooh baby, don't you know I suffer? And I love you", "en", true},
+		{"french", "Non, je ne regrette rien, c'est payé, balayé, oublié", "fr", true},
+		{"german", "Du hast mich gefragt und ich hab nichts gesagt", "de", true},
+		{"spanish", "Despacito, quiero respirar tu cuello despacito y que me", "es", true},
+		{"italian", "Nel blu dipinto di blu, felice di stare lassù, e non sono", "it", true},
+		{"portuguese", "Eu sei que vou te amar, por toda a minha vida, você", "pt", true},
+		{"japanese with kanji", "夜に駆ける 沈むように溶けてゆくように", "ja", true},
+		{"chinese", "月亮代表我的心 你问我爱你有多深", "zh", true},
+		{"korean", "사랑해요 당신을 정말로 사랑해요", "ko", true},
+		{"greek", "Σ' αγαπώ μ' αρέσει να σε βλέπω", "el", true},
+		{"too short", "la la", "", false},
+		{"latin without frequent words", "Lorem ipsum dolor sit amet consectetur", "", false},
+		{"no letters", "1234 5678 !!! ??? ... 90", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectLanguage(tt.text)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DetectLanguage(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsLanguage(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"en", true},
+		{"und", true},
+		{"EN", false},
+		{"e", false},
+		{"engl", false},
+		{"en-US", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsLanguage(tt.value); got != tt.want {
+			t.Errorf("IsLanguage(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
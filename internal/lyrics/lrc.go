@@ -0,0 +1,254 @@
+package lyrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// _defaultLastCue is the duration of the last subtitle cue when the LRC has no [length:] tag.
+const _defaultLastCue = 5000
+
+var (
+	// lrcTimestamp is a line timestamp [mm:ss], [mm:ss.xx] or [mm:ss.xxx].
+	lrcTimestamp = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	// lrcWord is a word timestamp of enhanced LRC, <mm:ss.xx>.
+	lrcWord = regexp.MustCompile(`<(\d+):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+	// lrcTag is an ID tag like [ar:Muse].
+	lrcTag = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+)
+
+// ParseLRC parses LRC with optional enhanced word timestamps. A line may have several timestamps,
+// the [offset:] tag is applied to all times and the lines are ordered by time.
+func ParseLRC(text string) (model.SyncedLyrics, error) {
+	var (
+		result model.SyncedLyrics
+		offset int64
+	)
+	text = strings.TrimPrefix(Normalize(text), "\ufeff")
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var starts []int64
+		for {
+			match := lrcTimestamp.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			start, err := lrcTime(match[1:])
+			if err != nil {
+				return model.SyncedLyrics{}, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			starts = append(starts, start)
+			line = line[len(match[0]):]
+		}
+		if len(starts) > 0 {
+			lineText, words, err := parseWords(line, starts[0])
+			if err != nil {
+				return model.SyncedLyrics{}, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			for _, start := range starts {
+				result.Lines = append(result.Lines, model.SyncedLine{
+					StartMs: start,
+					Text:    lineText,
+					Words:   shiftWords(words, start-starts[0]),
+				})
+			}
+			continue
+		}
+		tag := lrcTag.FindStringSubmatch(line)
+		if tag == nil {
+			return model.SyncedLyrics{}, fmt.Errorf("line %d has no timestamp", n+1)
+		}
+		value := strings.TrimSpace(tag[2])
+		switch strings.ToLower(tag[1]) {
+		case "ti":
+			result.Title = value
+		case "ar":
+			result.Artist = value
+		case "al":
+			result.Album = value
+		case "length":
+			match := lrcTimestamp.FindStringSubmatch("[" + value + "]")
+			if match == nil {
+				return model.SyncedLyrics{}, fmt.Errorf("line %d has invalid length %q", n+1, value)
+			}
+			length, err := lrcTime(match[1:])
+			if err != nil {
+				return model.SyncedLyrics{}, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			result.LengthMs = length
+		case "offset":
+			parsed, err := strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 64)
+			if err != nil {
+				return model.SyncedLyrics{}, fmt.Errorf("line %d has invalid offset %q", n+1, value)
+			}
+			offset = parsed
+		}
+	}
+	if len(result.Lines) == 0 {
+		return model.SyncedLyrics{}, errors.New("lrc has no timed lines")
+	}
+	// A positive offset shows the lyrics earlier.
+	for i := range result.Lines {
+		result.Lines[i].StartMs = max(result.Lines[i].StartMs-offset, 0)
+		for j := range result.Lines[i].Words {
+			result.Lines[i].Words[j].StartMs = max(result.Lines[i].Words[j].StartMs-offset, 0)
+		}
+	}
+	sort.SliceStable(result.Lines, func(i, j int) bool {
+		return result.Lines[i].StartMs < result.Lines[j].StartMs
+	})
+	for i := range result.Lines {
+		if i+1 < len(result.Lines) {
+			result.Lines[i].EndMs = result.Lines[i+1].StartMs
+		} else if result.LengthMs > result.Lines[i].StartMs {
+			result.Lines[i].EndMs = result.LengthMs
+		}
+	}
+	return result, nil
+}
+
+// lrcTime converts the minutes, seconds and fraction groups of a timestamp to milliseconds.
+func lrcTime(groups []string) (int64, error) {
+	minutes, _ := strconv.ParseInt(groups[0], 10, 64)
+	seconds, _ := strconv.ParseInt(groups[1], 10, 64)
+	if seconds >= 60 {
+		return 0, fmt.Errorf("timestamp %s:%s has more than 59 seconds", groups[0], groups[1])
+	}
+	ms := (minutes*60 + seconds) * 1000
+	if fraction := groups[2]; fraction != "" {
+		value, _ := strconv.ParseInt(fraction, 10, 64)
+		for i := len(fraction); i < 3; i++ {
+			value *= 10
+		}
+		ms += value
+	}
+	return ms, nil
+}
+
+// parseWords removes word timestamps from the text of the line. Text before the first timestamp
+// starts with the line, a timestamp at the end only marks the end of the last word.
+func parseWords(text string, lineStart int64) (string, []model.SyncedWord, error) {
+	matches := lrcWord.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return strings.TrimSpace(text), nil, nil
+	}
+	var words []model.SyncedWord
+	if head := text[:matches[0][0]]; strings.TrimSpace(head) != "" {
+		words = append(words, model.SyncedWord{StartMs: lineStart, Text: head})
+	}
+	for i, match := range matches {
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		groups := []string{text[match[2]:match[3]], text[match[4]:match[5]], ""}
+		if match[6] >= 0 {
+			groups[2] = text[match[6]:match[7]]
+		}
+		start, err := lrcTime(groups)
+		if err != nil {
+			return "", nil, err
+		}
+		word := text[match[1]:end]
+		if strings.TrimSpace(word) == "" {
+			continue
+		}
+		words = append(words, model.SyncedWord{StartMs: start, Text: word})
+	}
+	var line strings.Builder
+	for _, word := range words {
+		line.WriteString(word.Text)
+	}
+	return strings.TrimSpace(line.String()), words, nil
+}
+
+func shiftWords(words []model.SyncedWord, delta int64) []model.SyncedWord {
+	shifted := slices.Clone(words)
+	for i := range shifted {
+		shifted[i].StartMs += delta
+	}
+	return shifted
+}
+
+// FormatVTT renders the lines as WebVTT cues, lines without text are skipped.
+func FormatVTT(lyrics model.SyncedLyrics) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for _, cue := range cues(lyrics) {
+		text := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(cue.Text)
+		fmt.Fprintf(&b, "\n%s --> %s\n%s\n", cueTime(cue.StartMs, '.'), cueTime(cue.EndMs, '.'), text)
+	}
+	return b.String()
+}
+
+// FormatSRT renders the lines as numbered SubRip cues, lines without text are skipped.
+func FormatSRT(lyrics model.SyncedLyrics) string {
+	var b strings.Builder
+	for i, cue := range cues(lyrics) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n", i+1, cueTime(cue.StartMs, ','), cueTime(cue.EndMs, ','), cue.Text)
+	}
+	return b.String()
+}
+
+// cues are the lines with text, every cue ends when the next line starts.
+func cues(lyrics model.SyncedLyrics) []model.SyncedLine {
+	var result []model.SyncedLine
+	for _, line := range lyrics.Lines {
+		if line.Text == "" {
+			continue
+		}
+		if line.EndMs <= line.StartMs {
+			line.EndMs = line.StartMs + _defaultLastCue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// cueTime formats milliseconds as hh:mm:ss.mmm, SubRip uses a comma before milliseconds.
+func cueTime(ms int64, separator byte) string {
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
+}
+
+// Active returns the line sung at atMs and its word for enhanced LRC. After the end of the last line,
+// known from [length:], no line is active and the lyrics have ended.
+func Active(lyrics model.SyncedLyrics, atMs int64) model.ActiveLine {
+	result := model.ActiveLine{AtMs: atMs, Index: -1}
+	// The first line starting after atMs, the active one is before it.
+	next := sort.Search(len(lyrics.Lines), func(i int) bool {
+		return lyrics.Lines[i].StartMs > atMs
+	})
+	if next < len(lyrics.Lines) {
+		result.Next = &lyrics.Lines[next]
+	}
+	if next == 0 {
+		return result
+	}
+	line := lyrics.Lines[next-1]
+	if next == len(lyrics.Lines) && line.EndMs > line.StartMs && atMs >= line.EndMs {
+		result.Ended = true
+		return result
+	}
+	result.Index = next - 1
+	result.Line = &line
+	for i := len(line.Words) - 1; i >= 0; i-- {
+		if line.Words[i].StartMs <= atMs {
+			result.Word = &line.Words[i]
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,199 @@
+package lyrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name    string
+		lrc     string
+		want    model.SyncedLyrics
+		wantErr string
+	}{
+		{
+			name: "tags and lines",
+			lrc:  "\ufeff[ti:Uprising]\r\n[ar:Muse]\r\n[al:The Resistance]\r\n[length:01:00]\r\n[00:01.50]Paranoia\r\n[00:05]is in bloom",
+			want: model.SyncedLyrics{
+				Title:    "Uprising",
+				Artist:   "Muse",
+				Album:    "The Resistance",
+				LengthMs: 60000,
+				Lines: []model.SyncedLine{
+					{StartMs: 1500, EndMs: 5000, Text: "Paranoia"},
+					{StartMs: 5000, EndMs: 60000, Text: "is in bloom"},
+				},
+			},
+		},
+		{
+			name: "fractions of one, two and three digits",
+			lrc:  "[00:01.5]a\n[00:02.25]b\n[00:03.125]c",
+			want: model.SyncedLyrics{Lines: []model.SyncedLine{
+				{StartMs: 1500, EndMs: 2250, Text: "a"},
+				{StartMs: 2250, EndMs: 3125, Text: "b"},
+				{StartMs: 3125, Text: "c"},
+			}},
+		},
+		{
+			name: "several timestamps on a line are sorted",
+			lrc:  "[00:10.00][00:30.00]chorus\n[00:20.00]verse",
+			want: model.SyncedLyrics{Lines: []model.SyncedLine{
+				{StartMs: 10000, EndMs: 20000, Text: "chorus"},
+				{StartMs: 20000, EndMs: 30000, Text: "verse"},
+				{StartMs: 30000, Text: "chorus"},
+			}},
+		},
+		{
+			name: "offset shows the lyrics earlier",
+			lrc:  "[offset:+500]\n[00:00.20]a\n[00:02.00]b",
+			want: model.SyncedLyrics{Lines: []model.SyncedLine{
+				{StartMs: 0, EndMs: 1500, Text: "a"},
+				{StartMs: 1500, Text: "b"},
+			}},
+		},
+		{
+			name: "enhanced word timestamps",
+			lrc:  "[00:10.00]<00:10.00>Hello <00:10.50>world<00:11.00>\n[00:12.00][00:20.00]So <00:12.40>long",
+			want: model.SyncedLyrics{Lines: []model.SyncedLine{
+				{StartMs: 10000, EndMs: 12000, Text: "Hello world", Words: []model.SyncedWord{
+					{StartMs: 10000, Text: "Hello "},
+					{StartMs: 10500, Text: "world"},
+				}},
+				{StartMs: 12000, EndMs: 20000, Text: "So long", Words: []model.SyncedWord{
+					{StartMs: 12000, Text: "So "},
+					{StartMs: 12400, Text: "long"},
+				}},
+				{StartMs: 20000, Text: "So long", Words: []model.SyncedWord{
+					{StartMs: 20000, Text: "So "},
+					{StartMs: 20400, Text: "long"},
+				}},
+			}},
+		},
+		{
+			name: "empty line keeps its time",
+			lrc:  "[00:01.00]a\n[00:02.00]\n[00:03.00]b",
+			want: model.SyncedLyrics{Lines: []model.SyncedLine{
+				{StartMs: 1000, EndMs: 2000, Text: "a"},
+				{StartMs: 2000, EndMs: 3000, Text: ""},
+				{StartMs: 3000, Text: "b"},
+			}},
+		},
+		{name: "no timed lines", lrc: "[ti:Title]", wantErr: "no timed lines"},
+		{name: "line without timestamp", lrc: "[00:01.00]a\nplain text", wantErr: "line 2 has no timestamp"},
+		{name: "invalid offset", lrc: "[offset:soon]\n[00:01.00]a", wantErr: "invalid offset"},
+		{name: "seconds above 59", lrc: "[00:75.00]a", wantErr: "more than 59 seconds"},
+		{name: "word seconds above 59", lrc: "[00:01.00]<00:60.00>a", wantErr: "more than 59 seconds"},
+		{name: "length seconds above 59", lrc: "[length:03:99]\n[00:01.00]a", wantErr: "more than 59 seconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLRC(tt.lrc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseLRC() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLRC() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLRC() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActive(t *testing.T) {
+	lyrics := model.SyncedLyrics{
+		LengthMs: 30000,
+		Lines: []model.SyncedLine{
+			{StartMs: 10000, EndMs: 20000, Text: "Hello world", Words: []model.SyncedWord{
+				{StartMs: 10000, Text: "Hello "},
+				{StartMs: 10500, Text: "world"},
+			}},
+			{StartMs: 20000, EndMs: 30000, Text: "Bye"},
+		},
+	}
+	unknownEnd := model.SyncedLyrics{Lines: []model.SyncedLine{{StartMs: 1000, Text: "a"}}}
+	tests := []struct {
+		name      string
+		lyrics    model.SyncedLyrics
+		atMs      int64
+		wantIndex int
+		wantWord  string
+		wantNext  int64
+		wantEnded bool
+	}{
+		{name: "before the first line", lyrics: lyrics, atMs: 0, wantIndex: -1, wantNext: 10000},
+		{name: "line start", lyrics: lyrics, atMs: 10000, wantIndex: 0, wantWord: "Hello ", wantNext: 20000},
+		{name: "second word", lyrics: lyrics, atMs: 15000, wantIndex: 0, wantWord: "world", wantNext: 20000},
+		{name: "last line", lyrics: lyrics, atMs: 29999, wantIndex: 1},
+		{name: "end of the last line", lyrics: lyrics, atMs: 30000, wantIndex: -1, wantEnded: true},
+		{name: "after the length", lyrics: lyrics, atMs: 90000, wantIndex: -1, wantEnded: true},
+		{name: "last line without a known end", lyrics: unknownEnd, atMs: 90000, wantIndex: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Active(tt.lyrics, tt.atMs)
+			if got.AtMs != tt.atMs || got.Index != tt.wantIndex || got.Ended != tt.wantEnded {
+				t.Fatalf("Active() = %+v, want index %d and ended %v", got, tt.wantIndex, tt.wantEnded)
+			}
+			if (got.Line != nil) != (tt.wantIndex >= 0) {
+				t.Errorf("Active().Line = %+v, want a line only for index %d", got.Line, tt.wantIndex)
+			}
+			word := ""
+			if got.Word != nil {
+				word = got.Word.Text
+			}
+			if word != tt.wantWord {
+				t.Errorf("Active().Word = %q, want %q", word, tt.wantWord)
+			}
+			var next int64
+			if got.Next != nil {
+				next = got.Next.StartMs
+			}
+			if next != tt.wantNext {
+				t.Errorf("Active().Next starts at %d, want %d", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestFormatSubtitles(t *testing.T) {
+	lyrics := model.SyncedLyrics{Lines: []model.SyncedLine{
+		{StartMs: 1500, EndMs: 3723004, Text: "Rock & <roll>"},
+		{StartMs: 3723004, EndMs: 3724000, Text: ""},
+		{StartMs: 3724000, Text: "end"},
+	}}
+	tests := []struct {
+		name   string
+		format func(model.SyncedLyrics) string
+		want   string
+	}{
+		{
+			name:   "webvtt",
+			format: FormatVTT,
+			want: "WEBVTT\n" +
+				"\n00:00:01.500 --> 01:02:03.004\nRock &amp; &lt;roll&gt;\n" +
+				"\n01:02:04.000 --> 01:02:09.000\nend\n",
+		},
+		{
+			name:   "subrip",
+			format: FormatSRT,
+			want: "1\n00:00:01,500 --> 01:02:03,004\nRock & <roll>\n" +
+				"\n2\n01:02:04,000 --> 01:02:09,000\nend\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format(lyrics); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
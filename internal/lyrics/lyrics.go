@@ -0,0 +1,237 @@
+// Package lyrics parses song texts into sections with repeats and chorus detection.
+package lyrics
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// sectionKeywords maps the first word of a marker like [Chorus 2] or "Припев:" to the section type.
+var sectionKeywords = map[string]string{
+	"intro":      model.SectionIntro,
+	"интро":      model.SectionIntro,
+	"вступление": model.SectionIntro,
+	"verse":      model.SectionVerse,
+	"куплет":     model.SectionVerse,
+	"chorus":     model.SectionChorus,
+	"refrain":    model.SectionChorus,
+	"hook":       model.SectionChorus,
+	"припев":     model.SectionChorus,
+	"bridge":     model.SectionBridge,
+	"бридж":      model.SectionBridge,
+	"переход":    model.SectionBridge,
+	"outro":      model.SectionOutro,
+	"coda":       model.SectionOutro,
+	"аутро":      model.SectionOutro,
+	"кода":       model.SectionOutro,
+}
+
+// Version is the version of Parse. Sections stored by an older version are parsed again, so it has to be
+// increased whenever Parse returns different sections for the same text.
+const Version = 1
+
+var (
+	// repeatSuffix matches x2, (x2), ×3, 2x and the Cyrillic х2 at the end of a marker or as a whole line.
+	repeatSuffix = regexp.MustCompile(`(?i)[(\[]?\s*(?:[xх×]\s*(\d+)|(\d+)\s*[xх×])\s*[)\]]?$`)
+)
+
+// Normalize converts Windows and old Mac line endings to \n, trims trailing spaces of the lines
+// and drops leading and trailing blank lines.
+func Normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// section is a section being parsed, marked sections started with a marker line.
+type section struct {
+	model.LyricsSection
+	marked bool
+}
+
+// Parse splits the text into sections. Sections are separated by blank lines or marker lines like [Chorus],
+// (Chorus x2) or "Chorus:". A marker without lines repeats the earlier section with the same label or type,
+// and an unmarked section whose lines appear again is a chorus. Parse returns nil for empty texts.
+func Parse(text string) []model.LyricsSection {
+	var sections []section
+	var current *section
+	flush := func() {
+		if current != nil && (len(current.Lines) > 0 || current.marked) {
+			sections = append(sections, *current)
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(Normalize(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		if marker, ok := parseMarker(line); ok {
+			flush()
+			current = &marker
+			continue
+		}
+		if times, ok := parseRepeat(line); ok {
+			// A standalone x2 repeats the section it ends or the one before the blank line.
+			switch {
+			case current != nil:
+				current.Repeat = times
+				continue
+			case len(sections) > 0:
+				sections[len(sections)-1].Repeat = times
+				continue
+			}
+		}
+		if current == nil {
+			current = &section{LyricsSection: model.LyricsSection{Type: model.SectionVerse}}
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	flush()
+	return resolveRepeats(sections)
+}
+
+// parseMarker recognizes marker lines. Anything in square brackets is a marker, parentheses and a trailing
+// colon are markers only with a known keyword, so lines like "(oh-oh)" stay lyrics.
+func parseMarker(line string) (section, bool) {
+	var inner string
+	bracketed := false
+	switch {
+	case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+		inner, bracketed = line[1:len(line)-1], true
+	case strings.HasPrefix(line, "(") && strings.HasSuffix(line, ")"):
+		inner = line[1 : len(line)-1]
+	case strings.HasSuffix(line, ":"):
+		inner = strings.TrimSuffix(line, ":")
+	default:
+		return section{}, false
+	}
+	inner = strings.TrimSpace(inner)
+	repeat := 0
+	if match := repeatSuffix.FindStringSubmatchIndex(inner); match != nil {
+		repeat = repeatCount(inner, match)
+		inner = strings.TrimSpace(inner[:match[0]])
+		inner = strings.TrimRight(inner, " :-–—,")
+	}
+	sectionType, known := sectionKeywords[keyword(inner)]
+	if !known {
+		if !bracketed || inner == "" {
+			return section{}, false
+		}
+		sectionType = model.SectionVerse
+	}
+	return section{
+		LyricsSection: model.LyricsSection{Type: sectionType, Label: inner, Repeat: repeat},
+		marked:        true,
+	}, true
+}
+
+// parseRepeat recognizes a line consisting of a repeat marker only.
+func parseRepeat(line string) (int, bool) {
+	match := repeatSuffix.FindStringSubmatchIndex(line)
+	if match == nil || match[0] != 0 {
+		return 0, false
+	}
+	return repeatCount(line, match), true
+}
+
+func repeatCount(text string, match []int) int {
+	digits := 2
+	if match[2] < 0 {
+		digits = 4
+	}
+	times, _ := strconv.Atoi(text[match[digits]:match[digits+1]])
+	if times < 2 {
+		return 0
+	}
+	return times
+}
+
+// keyword returns the lowercased first word of the marker without digits, "Verse 2" gives "verse".
+func keyword(label string) string {
+	word, _, _ := strings.Cut(strings.ToLower(label), " ")
+	return strings.TrimRightFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// resolveRepeats fills bare markers from the sections they repeat and links repeated sections to the first
+// occurrence of their lines. Unmarked sections sung more than once become choruses, a marker of either
+// occurrence gives its type to both.
+func resolveRepeats(sections []section) []model.LyricsSection {
+	result := make([]model.LyricsSection, 0, len(sections))
+	first := map[string]int{}
+	for i := range sections {
+		s := &sections[i]
+		if len(s.Lines) == 0 {
+			if j, ok := findRepeated(sections[:i], s.Type, s.Label); ok {
+				s.Lines = sections[j].Lines
+				s.RepeatOf = &j
+			}
+		} else {
+			key := strings.ToLower(strings.Join(s.Lines, "\n"))
+			if j, ok := first[key]; ok {
+				s.RepeatOf = &j
+				switch {
+				case !s.marked && !sections[j].marked:
+					sections[j].Type = model.SectionChorus
+					s.Type = model.SectionChorus
+				case !s.marked:
+					s.Type = sections[j].Type
+				case !sections[j].marked:
+					// The marker of the repeat names the earlier unmarked stanza too.
+					sections[j].Type = s.Type
+				}
+				// The type of the first occurrence is settled, later repeats follow it.
+				sections[j].marked = true
+				result[j].Type = sections[j].Type
+			} else {
+				first[key] = i
+			}
+		}
+		result = append(result, s.LyricsSection)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// findRepeated finds the section a bare marker refers to: the first one with the same label,
+// otherwise the first one of the same type.
+func findRepeated(sections []section, sectionType, label string) (int, bool) {
+	byType := -1
+	for i, s := range sections {
+		if len(s.Lines) == 0 || s.RepeatOf != nil {
+			continue
+		}
+		if label != "" && strings.EqualFold(s.Label, label) {
+			return i, true
+		}
+		if byType < 0 && s.Type == sectionType {
+			byType = i
+		}
+	}
+	return byType, byType >= 0
+}
+
+// Collapse drops the lines of sections that repeat an earlier one, they keep RepeatOf.
+func Collapse(sections []model.LyricsSection) []model.LyricsSection {
+	collapsed := make([]model.LyricsSection, len(sections))
+	for i, s := range sections {
+		if s.RepeatOf != nil {
+			s.Lines = nil
+		}
+		collapsed[i] = s
+	}
+	return collapsed
+}
@@ -0,0 +1,203 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func index(i int) *int {
+	return &i
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{"windows line endings", "a\r\nb\r\n\r\nc", "a\nb\n\nc"},
+		{"old mac line endings", "a\rb", "a\nb"},
+		{"trailing spaces", "a  \nb\t", "a\nb"},
+		{"outer blank lines", "\n\n a\n\n", " a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []model.LyricsSection
+	}{
+		{
+			name: "empty",
+			text: " \n\n",
+			want: nil,
+		},
+		{
+			name: "unmarked verses",
+			text: "a\nb\n\nc\nd",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Lines: []string{"a", "b"}},
+				{Type: model.SectionVerse, Lines: []string{"c", "d"}},
+			},
+		},
+		{
+			name: "windows line endings",
+			text: "a\r\nb\r\n\r\nc",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Lines: []string{"a", "b"}},
+				{Type: model.SectionVerse, Lines: []string{"c"}},
+			},
+		},
+		{
+			name: "markers",
+			text: "[Intro]\noh\n[Verse 1]\na\nПрипев:\nb\n(Bridge)\nc\n[Outro]\nd",
+			want: []model.LyricsSection{
+				{Type: model.SectionIntro, Label: "Intro", Lines: []string{"oh"}},
+				{Type: model.SectionVerse, Label: "Verse 1", Lines: []string{"a"}},
+				{Type: model.SectionChorus, Label: "Припев", Lines: []string{"b"}},
+				{Type: model.SectionBridge, Label: "Bridge", Lines: []string{"c"}},
+				{Type: model.SectionOutro, Label: "Outro", Lines: []string{"d"}},
+			},
+		},
+		{
+			name: "unknown bracketed marker is a verse",
+			text: "[Rap]\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Label: "Rap", Lines: []string{"a"}},
+			},
+		},
+		{
+			name: "parentheses without a keyword are lyrics",
+			text: "(oh-oh)\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Lines: []string{"(oh-oh)", "a"}},
+			},
+		},
+		{
+			name: "repeat in marker",
+			text: "[Chorus x2]\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}, Repeat: 2},
+			},
+		},
+		{
+			name: "standalone repeat line",
+			text: "a\nb\nx3\n\nc\n\n(2x)",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Lines: []string{"a", "b"}, Repeat: 3},
+				{Type: model.SectionVerse, Lines: []string{"c"}, Repeat: 2},
+			},
+		},
+		{
+			name: "bare marker repeats the section with the same label",
+			text: "[Chorus]\na\n\n[Verse]\nb\n\n[Chorus]",
+			want: []model.LyricsSection{
+				{Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}},
+				{Type: model.SectionVerse, Label: "Verse", Lines: []string{"b"}},
+				{Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}, RepeatOf: index(0)},
+			},
+		},
+		{
+			name: "unmarked repeated stanza is a chorus",
+			text: "a\n\nr\ns\n\nb\n\nR\nS",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Lines: []string{"a"}},
+				{Type: model.SectionChorus, Lines: []string{"r", "s"}},
+				{Type: model.SectionVerse, Lines: []string{"b"}},
+				{Type: model.SectionChorus, Lines: []string{"R", "S"}, RepeatOf: index(1)},
+			},
+		},
+		{
+			name: "unmarked repeat of a marked section takes its type",
+			text: "[Bridge]\na\n\nb\n\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionBridge, Label: "Bridge", Lines: []string{"a"}},
+				{Type: model.SectionVerse, Lines: []string{"b"}},
+				{Type: model.SectionBridge, Lines: []string{"a"}, RepeatOf: index(0)},
+			},
+		},
+		{
+			name: "marked repeat of an unmarked stanza keeps its marker",
+			text: "a\n\nb\n\n[Verse 3]\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionVerse, Lines: []string{"a"}},
+				{Type: model.SectionVerse, Lines: []string{"b"}},
+				{Type: model.SectionVerse, Label: "Verse 3", Lines: []string{"a"}, RepeatOf: index(0)},
+			},
+		},
+		{
+			name: "marked repeat names the unmarked stanza",
+			text: "a\n\nb\n\n[Chorus]\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionChorus, Lines: []string{"a"}},
+				{Type: model.SectionVerse, Lines: []string{"b"}},
+				{Type: model.SectionChorus, Label: "Chorus", Lines: []string{"a"}, RepeatOf: index(0)},
+			},
+		},
+		{
+			name: "inferred chorus is kept for a later marked repeat",
+			text: "a\n\na\n\n[Verse 3]\na",
+			want: []model.LyricsSection{
+				{Type: model.SectionChorus, Lines: []string{"a"}},
+				{Type: model.SectionChorus, Lines: []string{"a"}, RepeatOf: index(0)},
+				{Type: model.SectionVerse, Label: "Verse 3", Lines: []string{"a"}, RepeatOf: index(0)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []model.LyricsSection
+		want     []model.LyricsSection
+	}{
+		{
+			name:     "empty",
+			sections: nil,
+			want:     []model.LyricsSection{},
+		},
+		{
+			name: "repeats lose their lines",
+			sections: []model.LyricsSection{
+				{Type: model.SectionChorus, Lines: []string{"a"}},
+				{Type: model.SectionVerse, Lines: []string{"b"}},
+				{Type: model.SectionChorus, Lines: []string{"a"}, RepeatOf: index(0)},
+			},
+			want: []model.LyricsSection{
+				{Type: model.SectionChorus, Lines: []string{"a"}},
+				{Type: model.SectionVerse, Lines: []string{"b"}},
+				{Type: model.SectionChorus, RepeatOf: index(0)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]model.LyricsSection(nil), tt.sections...)
+			if got := Collapse(tt.sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Collapse() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.sections, original) {
+				t.Errorf("Collapse() changed its argument")
+			}
+		})
+	}
+}
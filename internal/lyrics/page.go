@@ -0,0 +1,127 @@
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// verseSeparator matches the blank lines between verses of a normalized text.
+var verseSeparator = regexp.MustCompile(`\n{2,}`)
+
+// Verses splits the text into verses separated by blank lines, Windows line endings included.
+func Verses(text string) []string {
+	text = Normalize(text)
+	if text == "" {
+		return []string{}
+	}
+	return verseSeparator.Split(text, -1)
+}
+
+// Lines returns the non-blank lines of the text.
+func Lines(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(Normalize(text), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Paginate returns the page of the text in unit, one of model.TextUnit*. For model.TextUnitChars
+// size is the number of characters of a page: lines are packed into pages without being split
+// unless a line alone is longer than size, then it is split between words.
+func Paginate(text, unit string, page, size int) model.TextPage {
+	verses, lines := Verses(text), Lines(text)
+	var pages [][]string
+	switch unit {
+	case model.TextUnitChars:
+		pages = packLines(lines, size)
+	case model.TextUnitLine:
+		pages = chunk(lines, size)
+	default:
+		pages = chunk(verses, size)
+	}
+	result := model.TextPage{
+		Items:       []string{},
+		Unit:        unit,
+		Page:        page,
+		PageSize:    size,
+		TotalVerses: len(verses),
+		TotalLines:  len(lines),
+		TotalPages:  len(pages),
+		HasNext:     page < len(pages),
+	}
+	if page <= len(pages) {
+		result.Items = pages[page-1]
+	}
+	return result
+}
+
+func chunk(items []string, size int) [][]string {
+	var pages [][]string
+	for start := 0; start < len(items); start += size {
+		pages = append(pages, items[start:min(len(items), start+size)])
+	}
+	return pages
+}
+
+// packLines groups lines into pages of at most budget characters, counting a newline between lines.
+func packLines(lines []string, budget int) [][]string {
+	var pages [][]string
+	var current []string
+	size := 0
+	for _, line := range lines {
+		for _, part := range splitLine(line, budget) {
+			length := utf8.RuneCountInString(part)
+			if len(current) > 0 && size+1+length > budget {
+				pages = append(pages, current)
+				current, size = nil, 0
+			}
+			if len(current) > 0 {
+				size++
+			}
+			current = append(current, part)
+			size += length
+		}
+	}
+	if len(current) > 0 {
+		pages = append(pages, current)
+	}
+	return pages
+}
+
+// splitLine splits a line longer than budget between words, words longer than budget are cut.
+func splitLine(line string, budget int) []string {
+	if utf8.RuneCountInString(line) <= budget {
+		return []string{line}
+	}
+	var parts []string
+	var current []rune
+	for _, word := range strings.Fields(line) {
+		runes := []rune(word)
+		for len(runes) > budget {
+			if len(current) > 0 {
+				parts = append(parts, string(current))
+				current = nil
+			}
+			parts = append(parts, string(runes[:budget]))
+			runes = runes[budget:]
+		}
+		if len(current) > 0 && len(current)+1+len(runes) > budget {
+			parts = append(parts, string(current))
+			current = nil
+		}
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+		current = append(current, runes...)
+	}
+	if len(current) > 0 {
+		parts = append(parts, string(current))
+	}
+	return parts
+}
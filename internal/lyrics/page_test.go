@@ -0,0 +1,108 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func TestPaginate(t *testing.T) {
+	text := "a b\r\nc d\r\n\r\n\r\ne f\r\n  \r\ng\r\n\r\nh"
+	tests := []struct {
+		name string
+		text string
+		unit string
+		page int
+		size int
+		want model.TextPage
+	}{
+		{
+			name: "verses with windows line endings",
+			text: text, unit: model.TextUnitVerse, page: 1, size: 2,
+			want: model.TextPage{
+				Items: []string{"a b\nc d", "e f"},
+				Unit:  model.TextUnitVerse, Page: 1, PageSize: 2,
+				TotalVerses: 4, TotalLines: 5, TotalPages: 2, HasNext: true,
+			},
+		},
+		{
+			name: "last verse page",
+			text: text, unit: model.TextUnitVerse, page: 2, size: 2,
+			want: model.TextPage{
+				Items: []string{"g", "h"},
+				Unit:  model.TextUnitVerse, Page: 2, PageSize: 2,
+				TotalVerses: 4, TotalLines: 5, TotalPages: 2,
+			},
+		},
+		{
+			name: "lines skip blank lines",
+			text: text, unit: model.TextUnitLine, page: 2, size: 2,
+			want: model.TextPage{
+				Items: []string{"e f", "g"},
+				Unit:  model.TextUnitLine, Page: 2, PageSize: 2,
+				TotalVerses: 4, TotalLines: 5, TotalPages: 3, HasNext: true,
+			},
+		},
+		{
+			name: "text without blank lines is one verse",
+			text: "a\nb\nc", unit: model.TextUnitLine, page: 1, size: 2,
+			want: model.TextPage{
+				Items: []string{"a", "b"},
+				Unit:  model.TextUnitLine, Page: 1, PageSize: 2,
+				TotalVerses: 1, TotalLines: 3, TotalPages: 2, HasNext: true,
+			},
+		},
+		{
+			name: "page after the end",
+			text: text, unit: model.TextUnitLine, page: 9, size: 2,
+			want: model.TextPage{
+				Items: []string{},
+				Unit:  model.TextUnitLine, Page: 9, PageSize: 2,
+				TotalVerses: 4, TotalLines: 5, TotalPages: 3,
+			},
+		},
+		{
+			name: "characters pack whole lines",
+			text: text, unit: model.TextUnitChars, page: 1, size: 7,
+			want: model.TextPage{
+				Items: []string{"a b", "c d"},
+				Unit:  model.TextUnitChars, Page: 1, PageSize: 7,
+				TotalVerses: 4, TotalLines: 5, TotalPages: 2, HasNext: true,
+			},
+		},
+		{
+			name: "characters are runes",
+			text: "жжж\nююю", unit: model.TextUnitChars, page: 1, size: 7,
+			want: model.TextPage{
+				Items: []string{"жжж", "ююю"},
+				Unit:  model.TextUnitChars, Page: 1, PageSize: 7,
+				TotalVerses: 1, TotalLines: 2, TotalPages: 1,
+			},
+		},
+		{
+			name: "long line is split between words and long words are cut",
+			text: "one two three fourfivesix seven", unit: model.TextUnitChars, page: 4, size: 5,
+			want: model.TextPage{
+				Items: []string{"fourf"},
+				Unit:  model.TextUnitChars, Page: 4, PageSize: 5,
+				TotalVerses: 1, TotalLines: 1, TotalPages: 7, HasNext: true,
+			},
+		},
+		{
+			name: "empty text",
+			text: "\r\n", unit: model.TextUnitVerse, page: 1, size: 10,
+			want: model.TextPage{
+				Items: []string{},
+				Unit:  model.TextUnitVerse, Page: 1, PageSize: 10,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Paginate(tt.text, tt.unit, tt.page, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Paginate() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
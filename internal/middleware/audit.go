@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/audit"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	ActorHeader     = "X-Actor"
+
+	// maxHeaderValue limits client supplied values stored in the revision history.
+	maxHeaderValue = 128
+)
+
+// RequestID keeps the X-Request-ID of the client or generates a new one and echoes it in the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := headerValue(r, RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(audit.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// Actor takes the name of the user making changes from the X-Actor header.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(audit.WithActor(r.Context(), headerValue(r, ActorHeader))))
+	})
+}
+
+func headerValue(r *http.Request, name string) string {
+	value := strings.TrimSpace(r.Header.Get(name))
+	if len(value) > maxHeaderValue {
+		value = value[:maxHeaderValue]
+	}
+	return value
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,80 @@
+package album
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+)
+
+type service struct {
+	albumStore core.AlbumStore
+	groupStore core.GroupStore
+}
+
+func New(store core.AlbumStore, groupStore core.GroupStore) core.AlbumService {
+	return &service{
+		albumStore: store,
+		groupStore: groupStore,
+	}
+}
+
+func (s *service) GetAlbums(ctx context.Context, filters model.AlbumFilters, page, pageSize int) ([]model.Album, error) {
+	return s.albumStore.GetAlbums(ctx, filters, page, pageSize)
+}
+
+func (s *service) GetAlbum(ctx context.Context, id int) (model.Album, error) {
+	return s.albumStore.GetAlbum(ctx, id)
+}
+
+func (s *service) DeleteAlbum(ctx context.Context, id int) error {
+	return s.albumStore.DeleteAlbum(ctx, id)
+}
+
+func (s *service) SetAlbumTracks(ctx context.Context, id int, songIDs []int) error {
+	seen := make(map[int]bool, len(songIDs))
+	for _, songID := range songIDs {
+		if songID <= 0 {
+			return apperror.Validation("invalid song id %d", songID)
+		}
+		if seen[songID] {
+			return apperror.Validation("song %d appears on the album twice", songID)
+		}
+		seen[songID] = true
+	}
+	return s.albumStore.SetAlbumTracks(ctx, id, songIDs)
+}
+
+// UpdateAlbum applies the merge patch, the album moves to the group with the given name (creating it if needed)
+// when patch.Group is set.
+func (s *service) UpdateAlbum(ctx context.Context, id int, patch model.AlbumPatch) error {
+	if patch.Group.Set && (patch.Group.Null || strings.TrimSpace(patch.Group.Value) == "") {
+		return apperror.Validation("group cannot be empty")
+	}
+	if patch.Title.Set && (patch.Title.Null || strings.TrimSpace(patch.Title.Value) == "") {
+		return apperror.Validation("title cannot be empty")
+	}
+	var groupID int
+	if patch.Group.Set {
+		group, err := s.groupStore.ResolveGroup(ctx, patch.Group.Value)
+		if err != nil {
+			return err
+		}
+		groupID = group.ID
+	}
+	return s.albumStore.UpdateAlbum(ctx, id, groupID, patch)
+}
+
+// CreateAlbum resolves the group by its name, creating the group if it does not exist yet.
+func (s *service) CreateAlbum(ctx context.Context, album model.AlbumCommon) (model.Album, error) {
+	if strings.TrimSpace(album.Group) == "" || strings.TrimSpace(album.Title) == "" {
+		return model.Album{}, apperror.Validation("group and title are required")
+	}
+	group, err := s.groupStore.ResolveGroup(ctx, album.Group)
+	if err != nil {
+		return model.Album{}, err
+	}
+	return s.albumStore.CreateAlbum(ctx, group.ID, album)
+}
@@ -0,0 +1,180 @@
+// Package enrichment runs the workers that fill song details from the external API in the background.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/audit"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/enrich"
+	"github.com/kleo-53/music-system/pkg/logger"
+)
+
+const (
+	_defaultWorkers      = 4
+	_defaultMaxAttempts  = 5
+	_defaultPollInterval = time.Second
+	_defaultLease        = time.Minute
+	_defaultRetryBase    = 10 * time.Second
+	_defaultRetryMax     = 10 * time.Minute
+	_defaultRefresh      = time.Hour
+
+	// actor is written to the history of songs changed by the workers.
+	actor = "enrichment"
+)
+
+// DetailsClient is the source of song details, see enrich.Client.
+type DetailsClient interface {
+	SongDetails(ctx context.Context, group, song string) (model.SongDetail, error)
+}
+
+type Pool struct {
+	queue  core.EnrichmentQueue
+	client DetailsClient
+
+	workers      int
+	maxAttempts  int
+	pollInterval time.Duration
+	lease        time.Duration
+	retryBase    time.Duration
+	retryMax     time.Duration
+
+	refreshInterval time.Duration
+}
+
+func New(queue core.EnrichmentQueue, client DetailsClient, opts ...Option) *Pool {
+	p := &Pool{
+		queue:        queue,
+		client:       client,
+		workers:      _defaultWorkers,
+		maxAttempts:  _defaultMaxAttempts,
+		pollInterval: _defaultPollInterval,
+		lease:        _defaultLease,
+		retryBase:    _defaultRetryBase,
+		retryMax:     _defaultRetryMax,
+
+		refreshInterval: _defaultRefresh,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Run starts the workers and blocks until ctx is cancelled and the current jobs are finished.
+func (p *Pool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	if p.refreshInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.refresh(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// refresh periodically queues songs that are still missing details, for example because the external API
+// was unavailable for longer than all attempts of their jobs. Songs imported with enrich=none and songs
+// the external API does not know are queued again only by POST /songs:enrich.
+func (p *Pool) refresh(ctx context.Context) {
+	ctx = audit.WithActor(ctx, actor)
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		queued, err := p.queue.EnqueueMissing(ctx, nil, false, time.Now().Add(-p.refreshInterval))
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Log().Error(ctx, "Failed to queue songs missing details: %s", err.Error())
+			}
+			continue
+		}
+		if queued > 0 {
+			logger.Log().Info(ctx, "%d songs missing details were queued for enrichment", queued)
+		}
+	}
+}
+
+func (p *Pool) work(ctx context.Context) {
+	ctx = audit.WithActor(ctx, actor)
+	for ctx.Err() == nil {
+		job, ok, err := p.queue.ClaimJob(ctx, p.lease)
+		if err != nil && ctx.Err() == nil {
+			logger.Log().Error(ctx, "Failed to claim enrichment job: %s", err.Error())
+		}
+		if err != nil || !ok {
+			select {
+			case <-ctx.Done():
+			case <-time.After(p.pollInterval):
+			}
+			continue
+		}
+		// A started job is finished even during shutdown, otherwise it waits for the lease to expire.
+		p.process(context.WithoutCancel(ctx), job)
+	}
+}
+
+func (p *Pool) process(ctx context.Context, job core.EnrichmentJob) {
+	jobCtx, cancel := context.WithTimeout(ctx, p.lease)
+	defer cancel()
+	details, err := p.client.SongDetails(jobCtx, job.Group, job.Song)
+	if err == nil {
+		if err := p.queue.CompleteJob(ctx, job, details); err != nil {
+			logger.Log().Error(ctx, "Failed to save details of song %d: %s", job.SongID, err.Error())
+		}
+		return
+	}
+	if errors.Is(err, enrich.ErrNotFound) {
+		logger.Log().Info(ctx, "Details providers do not know song %d", job.SongID)
+		if err := p.queue.MarkNotFound(ctx, job); err != nil {
+			logger.Log().Error(ctx, "Failed to mark song %d as not found: %s", job.SongID, err.Error())
+		}
+		return
+	}
+	if !retryable(err) || job.Attempts >= p.maxAttempts {
+		logger.Log().Warn(ctx, "Enrichment of song %d failed after %d attempts: %s", job.SongID, job.Attempts, err.Error())
+		if err := p.queue.FailJob(ctx, job, err.Error()); err != nil {
+			logger.Log().Error(ctx, "Failed to mark enrichment job %d as failed: %s", job.ID, err.Error())
+		}
+		return
+	}
+	runAt := time.Now().Add(p.retryDelay(job.Attempts))
+	logger.Log().Info(ctx, "Enrichment of song %d will be retried at %s: %s", job.SongID, runAt.Format(time.RFC3339), err.Error())
+	if err := p.queue.RetryJob(ctx, job, runAt, err.Error()); err != nil {
+		logger.Log().Error(ctx, "Failed to reschedule enrichment job %d: %s", job.ID, err.Error())
+	}
+}
+
+// retryable tells temporary failures of the external API from permanent ones like rejected requests.
+func retryable(err error) bool {
+	if errors.Is(err, enrich.ErrCircuitOpen) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var e *enrich.Error
+	return errors.As(err, &e) && e.Retryable
+}
+
+// retryDelay doubles with every attempt up to retryMax.
+func (p *Pool) retryDelay(attempts int) time.Duration {
+	delay := p.retryBase
+	for i := 1; i < attempts && delay < p.retryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, p.retryMax)
+}
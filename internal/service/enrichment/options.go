@@ -0,0 +1,35 @@
+package enrichment
+
+import "time"
+
+// Option -.
+type Option func(*Pool)
+
+// Workers sets how many jobs are processed at the same time.
+func Workers(workers int) Option {
+	return func(p *Pool) {
+		p.workers = workers
+	}
+}
+
+// MaxAttempts sets after how many failed attempts the job is marked as failed.
+func MaxAttempts(attempts int) Option {
+	return func(p *Pool) {
+		p.maxAttempts = attempts
+	}
+}
+
+// PollInterval sets how long an idle worker waits before looking for new jobs.
+func PollInterval(interval time.Duration) Option {
+	return func(p *Pool) {
+		p.pollInterval = interval
+	}
+}
+
+// RefreshInterval sets how often songs still missing details are queued again, 0 disables the refresh.
+// A song is queued at most once per interval.
+func RefreshInterval(interval time.Duration) Option {
+	return func(p *Pool) {
+		p.refreshInterval = interval
+	}
+}
@@ -0,0 +1,46 @@
+package group
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+)
+
+type service struct {
+	groupStore core.GroupStore
+}
+
+func New(store core.GroupStore) core.GroupService {
+	return &service{
+		groupStore: store,
+	}
+}
+
+func (s *service) GetGroups(ctx context.Context, name string, page, pageSize int) ([]model.Group, error) {
+	return s.groupStore.GetGroups(ctx, name, page, pageSize)
+}
+
+func (s *service) GetGroup(ctx context.Context, id int) (model.Group, error) {
+	return s.groupStore.GetGroup(ctx, id)
+}
+
+func (s *service) DeleteGroup(ctx context.Context, id int) error {
+	return s.groupStore.DeleteGroup(ctx, id)
+}
+
+func (s *service) UpdateGroup(ctx context.Context, id int, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return apperror.Validation("group name is required")
+	}
+	return s.groupStore.UpdateGroup(ctx, id, name)
+}
+
+func (s *service) CreateGroup(ctx context.Context, name string) (model.Group, error) {
+	if strings.TrimSpace(name) == "" {
+		return model.Group{}, apperror.Validation("group name is required")
+	}
+	return s.groupStore.CreateGroup(ctx, name)
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+// EnrichSong saves details just received from the external API for the song.
+func (s *service) EnrichSong(ctx context.Context, id int, details model.SongDetail, overwrite bool) (model.Song, error) {
+	song, err := s.songStore.FillSongDetails(ctx, id, details, overwrite)
+	if err != nil {
+		return model.Song{}, err
+	}
+	return song.ToModel(), nil
+}
+
+func (s *service) MarkSongNotFound(ctx context.Context, id int) (model.Song, error) {
+	song, err := s.songStore.MarkSongNotFound(ctx, id)
+	if err != nil {
+		return model.Song{}, err
+	}
+	return song.ToModel(), nil
+}
+
+// EnqueueEnrichment queues every song in the library that misses any of the details, all details when missing is empty.
+func (s *service) EnqueueEnrichment(ctx context.Context, missing []string, overwrite bool) (int64, error) {
+	if !s.enrichment {
+		return 0, apperror.Validation("enrichment of song details is disabled")
+	}
+	for _, detail := range missing {
+		if !slices.Contains(model.Details, detail) {
+			return 0, apperror.Validation("unknown song detail %q, expected one of %v", detail, model.Details)
+		}
+	}
+	return s.songStore.EnqueueMissing(ctx, missing, overwrite, time.Time{})
+}
@@ -0,0 +1,94 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+)
+
+// ImportSongs creates songs in batches and reports the result of every item, invalid items do not stop the import.
+// Items listed in invalid failed to decode and get that error. Songs with missing details are queued for enrichment
+// only when enqueue is true.
+func (s *service) ImportSongs(ctx context.Context, items []model.SongImport, invalid map[int]error, enqueue bool) (model.SongImportReport, error) {
+	if len(items) == 0 {
+		return model.SongImportReport{}, apperror.Validation("no songs to import")
+	}
+	if len(items) > model.MaxImportSongs {
+		return model.SongImportReport{}, apperror.Validation("at most %d songs can be imported at once", model.MaxImportSongs)
+	}
+	report := model.SongImportReport{Items: make([]model.SongImportResult, len(items))}
+	groups := map[string]model.Group{}
+	songs := make([]core.Song, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		report.Items[i] = model.SongImportResult{Index: i, Status: model.ImportFailed}
+		if err, ok := invalid[i]; ok {
+			report.Items[i].Error = err.Error()
+			continue
+		}
+		if strings.TrimSpace(item.Group) == "" || strings.TrimSpace(item.Song) == "" {
+			report.Items[i].Error = "group and song are required"
+			continue
+		}
+		group, err := s.resolveImportGroup(ctx, groups, item.Group)
+		if err != nil {
+			report.Items[i].Error = apperror.MessageOf(err)
+			continue
+		}
+		status := s.enrichmentStatus(item.Text, item.ReleaseDate, item.Link)
+		switch {
+		case status == model.EnrichmentDone:
+		case item.NotFound:
+			status = model.EnrichmentNotFound
+		case status == model.EnrichmentPending && !enqueue:
+			status = model.EnrichmentSkipped
+		}
+		songs = append(songs, core.Song{
+			GroupID:          group.ID,
+			Group:            core.Group{ID: group.ID, Name: group.Name},
+			Song:             item.Song,
+			Text:             item.Text,
+			ReleaseDate:      item.ReleaseDate,
+			Link:             item.Link,
+			DetailSources:    item.Sources,
+			EnrichmentStatus: status,
+		})
+		indexes = append(indexes, i)
+	}
+	if len(songs) > 0 {
+		for j, err := range s.songStore.CreateSongs(ctx, songs, s.importBatchSize) {
+			result := &report.Items[indexes[j]]
+			if err != nil {
+				result.Error = apperror.MessageOf(err)
+				continue
+			}
+			result.Status = model.ImportCreated
+			result.ID = songs[j].ID
+		}
+	}
+	for _, result := range report.Items {
+		if result.Status == model.ImportCreated {
+			report.Created++
+		} else {
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// resolveImportGroup resolves every group of the import once.
+func (s *service) resolveImportGroup(ctx context.Context, groups map[string]model.Group, name string) (model.Group, error) {
+	key := model.GroupKey(name)
+	if group, ok := groups[key]; ok {
+		return group, nil
+	}
+	group, err := s.groupStore.ResolveGroup(ctx, name)
+	if err != nil {
+		return model.Group{}, err
+	}
+	groups[key] = group
+	return group, nil
+}
@@ -0,0 +1,71 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/lyrics"
+)
+
+func (s *service) GetLyrics(ctx context.Context, id int, collapse bool) (model.Lyrics, error) {
+	result, err := s.songStore.GetLyrics(ctx, id)
+	if err != nil {
+		return model.Lyrics{}, err
+	}
+	if collapse {
+		result.Sections = lyrics.Collapse(result.Sections)
+	}
+	return result, nil
+}
+
+func (s *service) PutSyncedLyrics(ctx context.Context, id int, lrc string) (model.SyncedLyrics, error) {
+	synced, err := lyrics.ParseLRC(lrc)
+	if err != nil {
+		return model.SyncedLyrics{}, apperror.Validation("invalid lrc: %s", err.Error())
+	}
+	if err := s.songStore.PutSyncedLyrics(ctx, id, lrc, synced); err != nil {
+		return model.SyncedLyrics{}, err
+	}
+	synced.SongID = id
+	return synced, nil
+}
+
+func (s *service) GetSyncedLyrics(ctx context.Context, id int) (model.SyncedLyrics, error) {
+	synced, err := s.songStore.GetSyncedLyrics(ctx, id)
+	if err != nil {
+		return model.SyncedLyrics{}, err
+	}
+	return synced.Data, nil
+}
+
+// ExportSyncedLyrics returns LRC as it was uploaded, WebVTT and SubRip are rendered from the parsed lines.
+func (s *service) ExportSyncedLyrics(ctx context.Context, id int, format string) (string, error) {
+	if format != model.LyricsLRC && format != model.LyricsVTT && format != model.LyricsSRT {
+		return "", apperror.Validation("unknown lyrics format %q, expected lrc, vtt or srt", format)
+	}
+	synced, err := s.songStore.GetSyncedLyrics(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case model.LyricsVTT:
+		return lyrics.FormatVTT(synced.Data), nil
+	case model.LyricsSRT:
+		return lyrics.FormatSRT(synced.Data), nil
+	default:
+		return synced.Source, nil
+	}
+}
+
+func (s *service) GetActiveLine(ctx context.Context, id int, at time.Duration) (model.ActiveLine, error) {
+	if at < 0 {
+		return model.ActiveLine{}, apperror.Validation("playback offset cannot be negative")
+	}
+	synced, err := s.songStore.GetSyncedLyrics(ctx, id)
+	if err != nil {
+		return model.ActiveLine{}, err
+	}
+	return lyrics.Active(synced.Data, at.Milliseconds()), nil
+}
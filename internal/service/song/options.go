@@ -0,0 +1,34 @@
+package user
+
+import "time"
+
+// Option -.
+type Option func(*service)
+
+// FuzzyThreshold sets the similarity threshold used when the request does not provide one.
+func FuzzyThreshold(threshold float64) Option {
+	return func(s *service) {
+		s.fuzzyThreshold = threshold
+	}
+}
+
+// TrashRetention sets how long deleted songs are kept in the trash before they can be purged.
+func TrashRetention(retention time.Duration) Option {
+	return func(s *service) {
+		s.trashRetention = retention
+	}
+}
+
+// ImportBatchSize sets how many songs a bulk import inserts with one statement.
+func ImportBatchSize(size int) Option {
+	return func(s *service) {
+		s.importBatchSize = size
+	}
+}
+
+// AsyncEnrichment makes new songs with missing details wait for the enrichment workers.
+func AsyncEnrichment() Option {
+	return func(s *service) {
+		s.enrichment = true
+	}
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"strings"
+	"unicode"
+)
+
+// buildTSQuery converts a user search string into a to_tsquery expression.
+// Words are combined with AND, "quoted words" become a phrase, word* is a prefix
+// and -word excludes songs containing the word. Everything except letters and digits
+// is dropped, so the result is always a valid tsquery or empty.
+func buildTSQuery(q string) string {
+	var terms []string
+	for len(q) > 0 {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		if q == "" {
+			break
+		}
+		negate := false
+		if q[0] == '-' {
+			negate = true
+			q = q[1:]
+		}
+		var term string
+		if strings.HasPrefix(q, `"`) {
+			end := strings.Index(q[1:], `"`)
+			var phrase string
+			if end < 0 {
+				phrase, q = q[1:], ""
+			} else {
+				phrase, q = q[1:end+1], q[end+2:]
+			}
+			term = joinLexemes(lexemes(phrase), " <-> ")
+		} else {
+			end := strings.IndexFunc(q, unicode.IsSpace)
+			var word string
+			if end < 0 {
+				word, q = q, ""
+			} else {
+				word, q = q[:end], q[end:]
+			}
+			words := lexemes(word)
+			if strings.HasSuffix(word, "*") && len(words) > 0 {
+				words[len(words)-1] += ":*"
+			}
+			term = joinLexemes(words, " <-> ")
+		}
+		if term == "" {
+			continue
+		}
+		if negate {
+			term = "!" + term
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " & ")
+}
+
+// lexemes splits text into quoted tsquery lexemes containing only letters and digits.
+func lexemes(text string) []string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = "'" + strings.ToLower(word) + "'"
+	}
+	return words
+}
+
+func joinLexemes(words []string, sep string) string {
+	if len(words) > 1 {
+		return "(" + strings.Join(words, sep) + ")"
+	}
+	return strings.Join(words, sep)
+}
@@ -0,0 +1,31 @@
+package user
+
+import "testing"
+
+func TestBuildTSQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want string
+	}{
+		{"empty", "  ", ""},
+		{"words are combined with and", "Muse  Uprising", "'muse' & 'uprising'"},
+		{"phrase", `"black hole"`, "('black' <-> 'hole')"},
+		{"unterminated phrase", `"deep purple`, "('deep' <-> 'purple')"},
+		{"prefix", "super*", "'super':*"},
+		{"prefix of a split word", "rock'n'roll*", "('rock' <-> 'n' <-> 'roll':*)"},
+		{"exclusion", "muse -live", "'muse' & !'live'"},
+		{"excluded phrase and prefix", `-"live at" wem*`, "!('live' <-> 'at') & 'wem':*"},
+		{"operators are dropped", "a & b | !c <-> (d):", "'a' & 'b' & 'c' & 'd'"},
+		{"quotes and backslashes are dropped", `it's \'x`, "('it' <-> 's') & 'x'"},
+		{"only punctuation", `- * ' : \ ""`, ""},
+		{"non-latin letters are lowercased", "Ёлка ДДТ", "'ёлка' & 'ддт'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildTSQuery(tt.q); got != tt.want {
+				t.Errorf("buildTSQuery(%q) = %q, want %q", tt.q, got, tt.want)
+			}
+		})
+	}
+}
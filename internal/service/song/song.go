@@ -2,37 +2,210 @@ package user
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/kleo-53/music-system/internal/apperror"
 	"github.com/kleo-53/music-system/internal/controller/model"
 	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/lyrics"
+)
+
+const (
+	_defaultFuzzyThreshold = 0.3
+	_defaultTrashRetention = 30 * 24 * time.Hour
+	_defaultImportBatch    = 500
 )
 
 type service struct {
-	songStore core.SongStore
+	songStore  core.SongStore
+	groupStore core.GroupStore
+
+	fuzzyThreshold  float64
+	trashRetention  time.Duration
+	importBatchSize int
+	enrichment      bool
 }
 
-func New(store core.SongStore) core.SongService {
-	return &service{
-		songStore: store,
+func New(store core.SongStore, groupStore core.GroupStore, opts ...Option) core.SongService {
+	s := &service{
+		songStore:       store,
+		groupStore:      groupStore,
+		fuzzyThreshold:  _defaultFuzzyThreshold,
+		trashRetention:  _defaultTrashRetention,
+		importBatchSize: _defaultImportBatch,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// sortFields are the fields the song listing can be sorted by.
+var sortFields = map[string]bool{
+	"group":        true,
+	"song":         true,
+	"release_date": true,
+	"id":           true,
 }
 
-func (s *service) GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) ([]model.Song, error) {
+func (s *service) GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) (model.SongPage, error) {
+	filters, err := s.validateFilters(filters)
+	if err != nil {
+		return model.SongPage{}, err
+	}
 	return s.songStore.GetSongsInfo(ctx, filters, page, pageSize)
 }
 
-func (s *service) GetSongText(ctx context.Context, id, page, pageSize int) ([]string, error) {
-	return s.songStore.GetSongText(ctx, id, page, pageSize)
+// ExportSongs streams all songs matching the filters to fn, the cursor of the filters is ignored.
+func (s *service) ExportSongs(ctx context.Context, filters model.SongFilters, fn func(model.Song) error) error {
+	filters, err := s.validateFilters(filters)
+	if err != nil {
+		return err
+	}
+	filters.Cursor = ""
+	return s.songStore.ExportSongs(ctx, filters, fn)
+}
+
+// validateFilters checks sort keys and the match mode and sets the default fuzzy threshold.
+func (s *service) validateFilters(filters model.SongFilters) (model.SongFilters, error) {
+	for _, key := range filters.Sort {
+		if !sortFields[key.Field] {
+			return model.SongFilters{}, apperror.Validation("cannot sort by %q", key.Field)
+		}
+	}
+	switch filters.Match {
+	case "", model.MatchExact:
+	case model.MatchFuzzy:
+		if filters.Threshold == nil {
+			threshold := s.fuzzyThreshold
+			filters.Threshold = &threshold
+		}
+		if threshold := *filters.Threshold; !(threshold >= 0 && threshold <= 1) {
+			return model.SongFilters{}, apperror.Validation("threshold must be between 0 and 1")
+		}
+	default:
+		return model.SongFilters{}, apperror.Validation("unknown match mode %q", filters.Match)
+	}
+	return filters, nil
+}
+
+// SearchSongs runs a full-text search over titles, group names and lyrics, see buildTSQuery for the query syntax.
+func (s *service) SearchSongs(ctx context.Context, query string, page, pageSize int) ([]model.SongSearchResult, error) {
+	tsQuery := buildTSQuery(query)
+	if tsQuery == "" {
+		return []model.SongSearchResult{}, apperror.Validation("search query has no words")
+	}
+	return s.songStore.SearchSongs(ctx, tsQuery, page, pageSize)
+}
+
+func (s *service) GetSong(ctx context.Context, id int) (model.Song, error) {
+	return s.songStore.GetSong(ctx, id)
+}
+
+func (s *service) GetSongText(ctx context.Context, id int, lang, unit string, page, pageSize int) (model.TextPage, error) {
+	if unit == "" {
+		unit = model.TextUnitVerse
+	}
+	if unit != model.TextUnitVerse && unit != model.TextUnitLine && unit != model.TextUnitChars {
+		return model.TextPage{}, apperror.Validation("unknown unit %q, expected verse, line or chars", unit)
+	}
+	if lang = strings.ToLower(lang); lang != "" {
+		version, err := s.GetLyricsVersion(ctx, id, lang)
+		if err != nil {
+			return model.TextPage{}, err
+		}
+		if version.Original {
+			lang = ""
+		}
+	}
+	text, err := s.songStore.GetSongText(ctx, id, lang)
+	if err != nil {
+		return model.TextPage{}, err
+	}
+	return lyrics.Paginate(text, unit, page, pageSize), nil
+}
+
+// DeleteSong moves the song to the trash, it can be restored until it is purged.
+func (s *service) DeleteSong(ctx context.Context, id int, versions []int) error {
+	return s.songStore.DeleteSong(ctx, id, versions)
+}
+
+func (s *service) RestoreSong(ctx context.Context, id int) error {
+	return s.songStore.RestoreSong(ctx, id)
+}
+
+// PurgeTrash permanently removes songs that were in the trash longer than the retention.
+func (s *service) PurgeTrash(ctx context.Context) (int64, error) {
+	return s.songStore.PurgeSongs(ctx, time.Now().Add(-s.trashRetention))
+}
+
+func (s *service) GetTrash(ctx context.Context, page, pageSize int) (model.SongPage, error) {
+	return s.songStore.GetTrash(ctx, page, pageSize)
+}
+
+func (s *service) GetSongHistory(ctx context.Context, id, page, pageSize int) ([]model.SongRevision, error) {
+	return s.songStore.GetSongHistory(ctx, id, page, pageSize)
 }
 
-func (s *service) DeleteSong(ctx context.Context, id int) error {
-	return s.songStore.DeleteSong(ctx, id)
+// RevertSong returns the song to its state after the given revision, the revert itself becomes a new revision.
+func (s *service) RevertSong(ctx context.Context, id, revision int) (model.Song, error) {
+	if revision < 1 {
+		return model.Song{}, apperror.Validation("revision must be positive")
+	}
+	if err := s.songStore.RevertSong(ctx, id, revision); err != nil {
+		return model.Song{}, err
+	}
+	return s.songStore.GetSong(ctx, id)
 }
 
-func (s *service) UpdateSong(ctx context.Context, id int, newData model.SongFilters) error {
-	return s.songStore.UpdateSong(ctx, id, newData)
+// UpdateSong applies a JSON Merge Patch to the song. The group is found by name or created,
+// group and song cannot be cleared while text, release date and link are cleared by null.
+func (s *service) UpdateSong(ctx context.Context, id int, patch model.SongPatch, versions []int) (model.Song, error) {
+	if patch.Group.Set && (patch.Group.Null || strings.TrimSpace(patch.Group.Value) == "") {
+		return model.Song{}, apperror.Validation("group cannot be empty")
+	}
+	if patch.Song.Set && (patch.Song.Null || strings.TrimSpace(patch.Song.Value) == "") {
+		return model.Song{}, apperror.Validation("song cannot be empty")
+	}
+	var groupID int
+	if patch.Group.Set {
+		group, err := s.groupStore.ResolveGroup(ctx, patch.Group.Value)
+		if err != nil {
+			return model.Song{}, err
+		}
+		groupID = group.ID
+	}
+	song, err := s.songStore.UpdateSong(ctx, id, groupID, patch, versions)
+	if err != nil {
+		return model.Song{}, err
+	}
+	return song.ToModel(), nil
 }
 
-func (s *service) CreateSong(ctx context.Context, song model.SongCommon, details model.SongDetail) error {
-	return s.songStore.CreateSong(ctx, song, details)
+// CreateSong resolves the group by its name, creating the group if it does not exist yet.
+// Missing details are filled later by the enrichment workers when enrichment is enabled.
+func (s *service) CreateSong(ctx context.Context, song model.SongCommon, details model.SongDetail) (core.Song, error) {
+	if strings.TrimSpace(song.Group) == "" || strings.TrimSpace(song.Song) == "" {
+		return core.Song{}, apperror.Validation("group and song are required")
+	}
+	group, err := s.groupStore.ResolveGroup(ctx, song.Group)
+	if err != nil {
+		return core.Song{}, err
+	}
+	return s.songStore.CreateSong(ctx, group.ID, song, details, s.enrichmentStatus(details.Text, details.ReleaseDate, details.Link))
+}
+
+// enrichmentStatus is pending for songs with missing details when they can be requested from the external API.
+func (s *service) enrichmentStatus(details ...string) string {
+	for _, detail := range details {
+		if detail != "" {
+			continue
+		}
+		if s.enrichment {
+			return model.EnrichmentPending
+		}
+		return model.EnrichmentSkipped
+	}
+	return model.EnrichmentDone
 }
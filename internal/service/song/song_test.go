@@ -0,0 +1,63 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/controller/model"
+)
+
+func TestValidateFiltersThreshold(t *testing.T) {
+	threshold := func(value float64) *float64 {
+		return &value
+	}
+	s := &service{fuzzyThreshold: 0.3}
+	tests := []struct {
+		name      string
+		threshold *float64
+		want      float64
+		wantErr   bool
+	}{
+		{name: "default", threshold: nil, want: 0.3},
+		{name: "zero is kept", threshold: threshold(0), want: 0},
+		{name: "one", threshold: threshold(1), want: 1},
+		{name: "negative", threshold: threshold(-0.1), wantErr: true},
+		{name: "above one", threshold: threshold(1.5), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.validateFilters(model.SongFilters{Match: model.MatchFuzzy, Threshold: tt.threshold})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("validateFilters() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateFilters() error = %v", err)
+			}
+			if got.Threshold == nil || *got.Threshold != tt.want {
+				t.Errorf("validateFilters() threshold = %v, want %v", got.Threshold, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFiltersSort(t *testing.T) {
+	s := &service{fuzzyThreshold: 0.3}
+	tests := []struct {
+		name    string
+		sort    []model.SortKey
+		wantErr bool
+	}{
+		{name: "no keys", sort: nil},
+		{name: "known keys", sort: []model.SortKey{{Field: "group"}, {Field: "song", Desc: true}, {Field: "release_date"}, {Field: "id"}}},
+		{name: "unknown field", sort: []model.SortKey{{Field: "song"}, {Field: "rating"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.validateFilters(model.SongFilters{Sort: tt.sort}); (err != nil) != tt.wantErr {
+				t.Errorf("validateFilters() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
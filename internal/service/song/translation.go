@@ -0,0 +1,102 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/lyrics"
+)
+
+func (s *service) AddLyricsVersion(ctx context.Context, id int, req model.LyricsVersionRequest) (model.LyricsVersion, error) {
+	lang := strings.ToLower(strings.TrimSpace(req.Lang))
+	if lang != "" && !lyrics.IsLanguage(lang) {
+		return model.LyricsVersion{}, apperror.Validation("invalid language %q, expected an ISO 639 code like en", req.Lang)
+	}
+	if !req.Original && strings.TrimSpace(req.Text) == "" {
+		return model.LyricsVersion{}, apperror.Validation("text of a translation is required")
+	}
+	detected := false
+	if lang == "" {
+		text := req.Text
+		if text == "" {
+			song, err := s.songStore.GetSong(ctx, id)
+			if err != nil {
+				return model.LyricsVersion{}, err
+			}
+			text = song.Text
+		}
+		var ok bool
+		if lang, ok = lyrics.DetectLanguage(text); !ok {
+			return model.LyricsVersion{}, apperror.Validation("cannot detect the language of the text, set lang")
+		}
+		detected = true
+	}
+	version, err := s.songStore.PutSongLyrics(ctx, id, core.SongLyrics{
+		SongID:   id,
+		Lang:     lang,
+		Original: req.Original,
+		Text:     req.Text,
+		Detected: detected,
+	})
+	if err != nil {
+		return model.LyricsVersion{}, err
+	}
+	return version.ToModel(), nil
+}
+
+// ListLyricsVersions detects the language of the original when it is not stored, songs without text
+// have no original.
+func (s *service) ListLyricsVersions(ctx context.Context, id int) ([]model.LyricsVersion, error) {
+	stored, err := s.songStore.ListSongLyrics(ctx, id)
+	if err != nil {
+		return []model.LyricsVersion{}, err
+	}
+	versions := make([]model.LyricsVersion, 0, len(stored))
+	for _, version := range stored {
+		versions = append(versions, version.ToModel())
+	}
+	if len(versions) == 0 || versions[0].Lang != "" {
+		return versions, nil
+	}
+	if versions[0].Text == "" {
+		return versions[1:], nil
+	}
+	versions[0].Lang = lyrics.Undetermined
+	if lang, ok := lyrics.DetectLanguage(versions[0].Text); ok && !hasLanguage(versions[1:], lang) {
+		versions[0].Lang, versions[0].Detected = lang, true
+	}
+	return versions, nil
+}
+
+func hasLanguage(versions []model.LyricsVersion, lang string) bool {
+	for _, version := range versions {
+		if version.Lang == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) GetLyricsVersion(ctx context.Context, id int, lang string) (model.LyricsVersion, error) {
+	lang = strings.ToLower(lang)
+	version, err := s.songStore.GetSongLyrics(ctx, id, lang)
+	if err == nil {
+		return version.ToModel(), nil
+	}
+	if !errors.Is(err, apperror.ErrNotFound) {
+		return model.LyricsVersion{}, err
+	}
+	// The language of the original may be detected rather than stored.
+	versions, listErr := s.ListLyricsVersions(ctx, id)
+	if listErr != nil {
+		return model.LyricsVersion{}, listErr
+	}
+	if len(versions) > 0 && versions[0].Original && versions[0].Lang == lang {
+		return versions[0], nil
+	}
+	return model.LyricsVersion{}, err
+}
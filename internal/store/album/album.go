@@ -0,0 +1,166 @@
+package album
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"github.com/kleo-53/music-system/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const entity = "album"
+
+type store struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) core.AlbumStore {
+	return &store{pg}
+}
+
+func convertToModelAlbum(album core.Album) model.Album {
+	result := model.Album{
+		ID:      album.ID,
+		GroupID: album.GroupID,
+		Group:   album.Group.Name,
+		Title:   album.Title,
+		Tracks:  []model.AlbumTrack{},
+	}
+	if album.ReleaseDate != nil {
+		result.ReleaseDate = &model.Date{Time: *album.ReleaseDate}
+	}
+	for _, track := range album.Tracks {
+		result.Tracks = append(result.Tracks, model.AlbumTrack{
+			Position: track.Position,
+			SongID:   track.SongID,
+			Song:     track.Song.Song,
+		})
+	}
+	return result
+}
+
+// withTracks loads the group and the tracklist ordered by position, songs in the trash are skipped.
+func withTracks(db *gorm.DB) *gorm.DB {
+	return db.
+		Joins("Group").
+		Preload("Tracks", func(db *gorm.DB) *gorm.DB {
+			return db.
+				Joins("JOIN songs ON songs.id = album_tracks.song_id AND songs.deleted_at IS NULL").
+				Order("album_tracks.position")
+		}).
+		Preload("Tracks.Song")
+}
+
+func (s *store) CreateAlbum(ctx context.Context, groupID int, album model.AlbumCommon) (model.Album, error) {
+	albumToAdd := core.Album{
+		GroupID: groupID,
+		Title:   album.Title,
+	}
+	if album.ReleaseDate != nil {
+		albumToAdd.ReleaseDate = &album.ReleaseDate.Time
+	}
+	if err := s.DB.WithContext(ctx).Omit(clause.Associations).Create(&albumToAdd).Error; err != nil {
+		return model.Album{}, dberr.TranslateError(err, entity)
+	}
+	return s.GetAlbum(ctx, albumToAdd.ID)
+}
+
+func (s *store) UpdateAlbum(ctx context.Context, id, groupID int, patch model.AlbumPatch) error {
+	updates := map[string]interface{}{}
+	if groupID != 0 {
+		updates["group_id"] = groupID
+	}
+	if patch.Title.Set {
+		updates["title"] = patch.Title.Value
+	}
+	switch {
+	case !patch.ReleaseDate.Set:
+	case patch.ReleaseDate.Null:
+		updates["release_date"] = nil
+	default:
+		updates["release_date"] = patch.ReleaseDate.Value.Time
+	}
+	if len(updates) == 0 {
+		err := s.DB.WithContext(ctx).Select("id").Where("id = ?", id).First(&core.Album{}).Error
+		return dberr.TranslateError(err, entity)
+	}
+	return dberr.RequireAffected(s.DB.WithContext(ctx).Model(&core.Album{}).Where("id = ?", id).Updates(updates), entity)
+}
+
+// SetAlbumTracks replaces the whole tracklist; songIDs[i] becomes track i+1.
+func (s *store) SetAlbumTracks(ctx context.Context, id int, songIDs []int) error {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var album core.Album
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&album).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("album_id = ?", id).Delete(&core.AlbumTrack{}).Error; err != nil {
+			return err
+		}
+		if len(songIDs) == 0 {
+			return nil
+		}
+		tracks := make([]core.AlbumTrack, 0, len(songIDs))
+		for i, songID := range songIDs {
+			tracks = append(tracks, core.AlbumTrack{
+				AlbumID:  id,
+				Position: i + 1,
+				SongID:   songID,
+			})
+		}
+		return tx.Omit(clause.Associations).Create(&tracks).Error
+	})
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return apperror.Validation("tracklist contains songs that do not exist")
+	}
+	return dberr.TranslateError(err, entity)
+}
+
+func (s *store) DeleteAlbum(ctx context.Context, id int) error {
+	return dberr.RequireAffected(s.DB.WithContext(ctx).Delete(&core.Album{}, "id = ?", id), entity)
+}
+
+func (s *store) GetAlbum(ctx context.Context, id int) (model.Album, error) {
+	var album core.Album
+	if err := withTracks(s.DB.WithContext(ctx)).
+		Where("albums.id = ?", id).
+		First(&album).Error; err != nil {
+		return model.Album{}, dberr.TranslateError(err, entity)
+	}
+	return convertToModelAlbum(album), nil
+}
+
+func (s *store) GetAlbums(ctx context.Context, filters model.AlbumFilters, page, pageSize int) ([]model.Album, error) {
+	var albums []core.Album
+	query := withTracks(s.DB.WithContext(ctx).Model(&core.Album{}))
+	if groupID := filters.GroupID; groupID != 0 {
+		query = query.Where("albums.group_id = ?", groupID)
+	}
+	if group := filters.Group; group != "" {
+		query = query.Where(`lower("Group".name) like lower(?)`, "%"+group+"%")
+	}
+	if title := filters.Title; title != "" {
+		query = query.Where("albums.title LIKE ?", "%"+title+"%")
+	}
+	if songID := filters.SongID; songID != 0 {
+		query = query.Where("exists (select 1 from album_tracks where album_tracks.album_id = albums.id and album_tracks.song_id = ?)", songID)
+	}
+	if err := query.
+		Order("albums.release_date nulls last").
+		Order("albums.id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&albums).Error; err != nil {
+		return []model.Album{}, dberr.TranslateError(err, entity)
+	}
+	responce := []model.Album{}
+	for _, album := range albums {
+		responce = append(responce, convertToModelAlbum(album))
+	}
+	return responce, nil
+}
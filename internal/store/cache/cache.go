@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"github.com/kleo-53/music-system/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const entity = "details cache entry"
+
+type store struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) core.DetailsCacheStore {
+	return &store{pg}
+}
+
+func (s *store) GetEntry(ctx context.Context, provider, songKey string) (core.DetailsCacheEntry, bool, error) {
+	var entry core.DetailsCacheEntry
+	err := s.DB.WithContext(ctx).
+		Where("provider = ? AND song_key = ? AND expires_at > now()", provider, songKey).
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return core.DetailsCacheEntry{}, false, nil
+	}
+	if err != nil {
+		return core.DetailsCacheEntry{}, false, dberr.TranslateError(err, entity)
+	}
+	return entry, true, nil
+}
+
+func (s *store) PutEntry(ctx context.Context, entry core.DetailsCacheEntry) error {
+	return dberr.TranslateError(s.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&entry).Error, entity)
+}
+
+func (s *store) DeleteExpired(ctx context.Context) (int64, error) {
+	result := s.DB.WithContext(ctx).
+		Where("expires_at <= now()").
+		Delete(&core.DetailsCacheEntry{})
+	return result.RowsAffected, dberr.TranslateError(result.Error, entity)
+}
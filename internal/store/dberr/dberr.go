@@ -0,0 +1,36 @@
+package dberr
+
+import (
+	"errors"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"gorm.io/gorm"
+)
+
+// TranslateError converts gorm errors to domain errors, entity is used in messages for clients.
+func TranslateError(err error, entity string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperror.NotFound("%s not found", entity)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperror.Conflict("%s already exists", entity)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperror.Conflict("%s conflicts with related data", entity)
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return apperror.Validation("%s has invalid data", entity)
+	}
+	return err
+}
+
+// RequireAffected turns an update or delete that touched no rows into a not found error.
+func RequireAffected(result *gorm.DB, entity string) error {
+	if result.Error != nil {
+		return TranslateError(result.Error, entity)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("%s not found", entity)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"github.com/kleo-53/music-system/pkg/postgres"
+)
+
+const entity = "group"
+
+type store struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) core.GroupStore {
+	return &store{pg}
+}
+
+func convertToModelGroup(group core.Group) model.Group {
+	return model.Group{
+		ID:   group.ID,
+		Name: group.Name,
+	}
+}
+
+// normalizeName trims the name and collapses inner whitespace, so "Muse " and "Muse" are stored the same way.
+// Case is compared by the lower(name) unique index.
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func (s *store) CreateGroup(ctx context.Context, name string) (model.Group, error) {
+	group := core.Group{Name: normalizeName(name)}
+	if err := s.DB.WithContext(ctx).Create(&group).Error; err != nil {
+		return model.Group{}, dberr.TranslateError(err, entity)
+	}
+	return convertToModelGroup(group), nil
+}
+
+func (s *store) ResolveGroup(ctx context.Context, name string) (model.Group, error) {
+	name = normalizeName(name)
+	if err := s.DB.WithContext(ctx).
+		Exec("insert into groups (name) values (?) on conflict ((lower(name))) do nothing", name).
+		Error; err != nil {
+		return model.Group{}, dberr.TranslateError(err, entity)
+	}
+	var group core.Group
+	if err := s.DB.WithContext(ctx).
+		Where("lower(name) = lower(?)", name).
+		First(&group).Error; err != nil {
+		return model.Group{}, dberr.TranslateError(err, entity)
+	}
+	return convertToModelGroup(group), nil
+}
+
+func (s *store) UpdateGroup(ctx context.Context, id int, name string) error {
+	return dberr.RequireAffected(s.DB.WithContext(ctx).
+		Model(&core.Group{}).
+		Where("id = ?", id).
+		Update("name", normalizeName(name)), entity)
+}
+
+func (s *store) DeleteGroup(ctx context.Context, id int) error {
+	err := dberr.RequireAffected(s.DB.WithContext(ctx).Delete(&core.Group{}, "id = ?", id), entity)
+	if errors.Is(err, apperror.ErrConflict) {
+		return apperror.Conflict("group %d still has songs or albums", id)
+	}
+	return err
+}
+
+func (s *store) GetGroup(ctx context.Context, id int) (model.Group, error) {
+	var group core.Group
+	if err := s.DB.WithContext(ctx).
+		Where("id = ?", id).
+		First(&group).Error; err != nil {
+		return model.Group{}, dberr.TranslateError(err, entity)
+	}
+	return convertToModelGroup(group), nil
+}
+
+func (s *store) GetGroups(ctx context.Context, name string, page, pageSize int) ([]model.Group, error) {
+	var groups []core.Group
+	query := s.DB.WithContext(ctx).Model(&core.Group{})
+	if name = normalizeName(name); name != "" {
+		query = query.Where("lower(name) LIKE lower(?)", "%"+name+"%")
+	}
+	if err := query.
+		Order("name").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&groups).Error; err != nil {
+		return []model.Group{}, dberr.TranslateError(err, entity)
+	}
+	responce := []model.Group{}
+	for _, group := range groups {
+		responce = append(responce, convertToModelGroup(group))
+	}
+	return responce, nil
+}
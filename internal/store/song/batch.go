@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/lyrics"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSongs inserts songs with their first revisions batch by batch, every batch in its own transaction.
+// When a batch fails its songs are inserted one by one, so one bad song does not fail its neighbours.
+// Songs must have Group set for the revision snapshot. errs[i] is the result for songs[i].
+func (s *store) CreateSongs(ctx context.Context, songs []core.Song, batchSize int) []error {
+	errs := make([]error, len(songs))
+	for i := range songs {
+		songs[i].Lyrics, songs[i].LyricsVersion = lyrics.Parse(songs[i].Text), lyrics.Version
+	}
+	for start := 0; start < len(songs); start += batchSize {
+		batch := songs[start:min(start+batchSize, len(songs))]
+		if err := s.createBatch(ctx, batch); err == nil {
+			continue
+		}
+		for i := range batch {
+			// IDs returned by the rolled back insert must not be reused.
+			batch[i].ID = 0
+		}
+		for i := range batch {
+			errs[start+i] = s.createBatch(ctx, batch[i:i+1])
+		}
+	}
+	return errs
+}
+
+func (s *store) createBatch(ctx context.Context, songs []core.Song) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).CreateInBatches(&songs, len(songs)).Error; err != nil {
+			if len(songs) == 1 && errors.Is(err, gorm.ErrForeignKeyViolated) {
+				return apperror.Validation("group %d does not exist", songs[0].GroupID)
+			}
+			return dberr.TranslateError(err, entity)
+		}
+		revisions := make([]core.SongRevision, 0, len(songs))
+		for i := range songs {
+			revision, err := newRevision(ctx, songs[i].ID, model.RevisionCreate, nil, &songs[i])
+			if err != nil {
+				return err
+			}
+			revision.Revision = 1
+			revisions = append(revisions, revision)
+		}
+		if err := tx.CreateInBatches(&revisions, len(revisions)).Error; err != nil {
+			return dberr.TranslateError(err, revisionEntity)
+		}
+		return enqueueJobs(tx, songs)
+	})
+}
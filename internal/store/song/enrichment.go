@@ -0,0 +1,303 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"github.com/kleo-53/music-system/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+const jobEntity = "enrichment job"
+
+type queue struct {
+	*postgres.Postgres
+}
+
+// NewEnrichmentQueue returns the queue of enrichment jobs, it lives with the songs store
+// because completing a job changes the song and its history.
+func NewEnrichmentQueue(pg *postgres.Postgres) core.EnrichmentQueue {
+	return &queue{pg}
+}
+
+// enqueueJobs adds a job for every song with the pending enrichment status, a song has at most one job.
+func enqueueJobs(tx *gorm.DB, songs []core.Song) error {
+	var jobs []core.EnrichmentJob
+	for _, song := range songs {
+		if song.EnrichmentStatus == model.EnrichmentPending {
+			jobs = append(jobs, core.EnrichmentJob{SongID: song.ID})
+		}
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	return dberr.TranslateError(tx.CreateInBatches(&jobs, len(jobs)).Error, jobEntity)
+}
+
+// ClaimJob leases the oldest due job. SKIP LOCKED lets every worker take a different job without waiting,
+// jobs of songs in the trash wait until the song is restored or purged.
+func (q *queue) ClaimJob(ctx context.Context, lease time.Duration) (core.EnrichmentJob, bool, error) {
+	var jobs []core.EnrichmentJob
+	if err := q.DB.WithContext(ctx).Raw(`
+		with next as (
+			select enrichment_jobs.id
+			from enrichment_jobs
+				join songs on songs.id = enrichment_jobs.song_id
+			where enrichment_jobs.status = 'queued' and enrichment_jobs.run_at <= now()
+				and (enrichment_jobs.locked_until is null or enrichment_jobs.locked_until < now())
+				and songs.deleted_at is null
+			order by enrichment_jobs.run_at, enrichment_jobs.id
+			limit 1
+			for update of enrichment_jobs skip locked
+		)
+		update enrichment_jobs
+		set attempts = enrichment_jobs.attempts + 1,
+			locked_until = now() + make_interval(secs => @lease)
+		from next, songs, groups
+		where enrichment_jobs.id = next.id
+			and songs.id = enrichment_jobs.song_id
+			and groups.id = songs.group_id
+		returning enrichment_jobs.*, groups.name as group_name, songs.song`,
+		sql.Named("lease", lease.Seconds()),
+	).Scan(&jobs).Error; err != nil {
+		return core.EnrichmentJob{}, false, dberr.TranslateError(err, jobEntity)
+	}
+	if len(jobs) == 0 {
+		return core.EnrichmentJob{}, false, nil
+	}
+	return jobs[0], true, nil
+}
+
+// CompleteJob fills only the details that are still empty unless the job overwrites them,
+// so edits made while the job waited are kept.
+func (q *queue) CompleteJob(ctx context.Context, job core.EnrichmentJob, details model.SongDetail) error {
+	return q.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), job.SongID)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return releaseJob(tx, job)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fillDetails(ctx, tx, before, details, job.Overwrite)
+		return err
+	})
+}
+
+func (q *queue) MarkNotFound(ctx context.Context, job core.EnrichmentJob) error {
+	return q.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), job.SongID)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return releaseJob(tx, job)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = markNotFound(tx, before.ID)
+		return err
+	})
+}
+
+func (s *store) MarkSongNotFound(ctx context.Context, id int) (core.Song, error) {
+	var updated core.Song
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), id)
+		if err != nil {
+			return err
+		}
+		updated, err = markNotFound(tx, before.ID)
+		return err
+	})
+	if err != nil {
+		return core.Song{}, err
+	}
+	return updated, nil
+}
+
+// markNotFound records the answer of the details providers that they do not know the locked song.
+// The details are kept, so a song enriched earlier does not lose them.
+func markNotFound(tx *gorm.DB, id int) (core.Song, error) {
+	if err := tx.Model(&core.Song{}).Where("id = ?", id).Updates(map[string]any{
+		"enrichment_status": model.EnrichmentNotFound,
+		"enriched_at":       gorm.Expr("now()"),
+	}).Error; err != nil {
+		return core.Song{}, dberr.TranslateError(err, entity)
+	}
+	if err := tx.Where("song_id = ?", id).Delete(&core.EnrichmentJob{}).Error; err != nil {
+		return core.Song{}, dberr.TranslateError(err, jobEntity)
+	}
+	return findSong(tx, id)
+}
+
+// releaseJob ends the lease of the job of a song that went to the trash while the job ran. The song stays pending
+// and the job waits until the song is restored, a purge removes the job together with the song.
+func releaseJob(tx *gorm.DB, job core.EnrichmentJob) error {
+	return dberr.TranslateError(tx.Model(&core.EnrichmentJob{}).
+		Where("id = ?", job.ID).
+		Update("locked_until", nil).Error, jobEntity)
+}
+
+func (s *store) FillSongDetails(ctx context.Context, id int, details model.SongDetail, overwrite bool) (core.Song, error) {
+	var updated core.Song
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), id)
+		if err != nil {
+			return err
+		}
+		updated, err = fillDetails(ctx, tx, before, details, overwrite)
+		return err
+	})
+	if err != nil {
+		return core.Song{}, err
+	}
+	return updated, nil
+}
+
+// fillDetails saves the details of the locked song, marks it as enriched and removes its job.
+// A revision is written only when a detail has changed, the enrichment columns alone keep
+// the version of the song, see migration 000010.
+func fillDetails(ctx context.Context, tx *gorm.DB, before core.Song, details model.SongDetail, overwrite bool) (core.Song, error) {
+	changes := map[string]any{
+		"enrichment_status": model.EnrichmentDone,
+		"enriched_at":       gorm.Expr("now()"),
+	}
+	fields := []struct{ detail, current, value string }{
+		{model.DetailText, before.Text, details.Text},
+		{model.DetailReleaseDate, before.ReleaseDate, details.ReleaseDate},
+		{model.DetailLink, before.Link, details.Link},
+	}
+	sources := maps.Clone(before.DetailSources)
+	changed := false
+	for _, field := range fields {
+		// The value is set when the provider knows it and the detail is empty or may be overwritten.
+		if field.value == "" || field.value == field.current || (field.current != "" && !overwrite) {
+			continue
+		}
+		changes[detailColumns[field.detail]] = field.value
+		if source := details.Sources[field.detail]; source != "" {
+			if sources == nil {
+				sources = map[string]string{}
+			}
+			sources[field.detail] = source
+		} else {
+			delete(sources, field.detail)
+		}
+		changed = true
+	}
+	if changed {
+		changes["detail_sources"] = sourcesColumn(sources)
+		setLyrics(changes)
+	}
+	if err := tx.Model(&core.Song{}).Where("id = ?", before.ID).Updates(changes).Error; err != nil {
+		return core.Song{}, dberr.TranslateError(err, entity)
+	}
+	if err := tx.Where("song_id = ?", before.ID).Delete(&core.EnrichmentJob{}).Error; err != nil {
+		return core.Song{}, dberr.TranslateError(err, jobEntity)
+	}
+	after, err := findSong(tx, before.ID)
+	if err != nil {
+		return core.Song{}, err
+	}
+	if !changed {
+		return after, nil
+	}
+	return after, writeRevision(ctx, tx, before.ID, model.RevisionUpdate, &before, &after)
+}
+
+func (q *queue) EnqueueMissing(ctx context.Context, details []string, overwrite bool, enrichedBefore time.Time) (int64, error) {
+	return enqueueMissing(q.DB.WithContext(ctx), details, overwrite, enrichedBefore)
+}
+
+func (s *store) EnqueueMissing(ctx context.Context, details []string, overwrite bool, enrichedBefore time.Time) (int64, error) {
+	return enqueueMissing(s.DB.WithContext(ctx), details, overwrite, enrichedBefore)
+}
+
+// detailColumns maps model.Detail* to the columns of songs.
+var detailColumns = map[string]string{
+	model.DetailText:        "song_text",
+	model.DetailReleaseDate: "release_date",
+	model.DetailLink:        "link",
+}
+
+// enqueueMissing creates or resets the jobs of the matching songs and marks them as pending in one statement.
+// A failed job of a song is reused, so the song keeps at most one job.
+func enqueueMissing(db *gorm.DB, details []string, overwrite bool, enrichedBefore time.Time) (int64, error) {
+	if len(details) == 0 {
+		details = model.Details
+	}
+	var missing []string
+	for _, detail := range details {
+		column, ok := detailColumns[detail]
+		if !ok {
+			return 0, apperror.Validation("unknown song detail %q", detail)
+		}
+		missing = append(missing, "coalesce(songs."+column+", '') = ''")
+	}
+	stale := ""
+	if !enrichedBefore.IsZero() {
+		// Skipped and not found songs are queued again only on explicit request.
+		stale = `and (songs.enriched_at is null or songs.enriched_at < @before)
+				and songs.enrichment_status not in ('skipped', 'not_found')`
+	}
+	result := db.Exec(`
+		with candidates as (
+			select songs.id
+			from songs
+			where songs.deleted_at is null and songs.enrichment_status <> 'pending'
+				and (`+strings.Join(missing, " or ")+`) `+stale+`
+		), queued as (
+			insert into enrichment_jobs (song_id, overwrite)
+			select id, @overwrite from candidates
+			on conflict (song_id) do update
+			set status = 'queued', attempts = 0, run_at = now(), locked_until = null,
+				last_error = '', overwrite = excluded.overwrite
+			returning song_id
+		)
+		update songs
+		set enrichment_status = 'pending'
+		from queued
+		where songs.id = queued.song_id`,
+		sql.Named("overwrite", overwrite),
+		sql.Named("before", enrichedBefore),
+	)
+	return result.RowsAffected, dberr.TranslateError(result.Error, jobEntity)
+}
+
+func (q *queue) RetryJob(ctx context.Context, job core.EnrichmentJob, runAt time.Time, reason string) error {
+	return dberr.TranslateError(q.DB.WithContext(ctx).
+		Model(&core.EnrichmentJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]any{
+			"run_at":       runAt,
+			"locked_until": nil,
+			"last_error":   reason,
+		}).Error, jobEntity)
+}
+
+func (q *queue) FailJob(ctx context.Context, job core.EnrichmentJob, reason string) error {
+	return q.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&core.EnrichmentJob{}).
+			Where("id = ?", job.ID).
+			Updates(map[string]any{
+				"status":       core.JobFailed,
+				"locked_until": nil,
+				"last_error":   reason,
+			}).Error; err != nil {
+			return dberr.TranslateError(err, jobEntity)
+		}
+		return dberr.TranslateError(tx.Model(&core.Song{}).
+			Where("id = ?", job.SongID).
+			Updates(map[string]any{
+				"enrichment_status": model.EnrichmentFailed,
+				"enriched_at":       gorm.Expr("now()"),
+			}).Error, entity)
+	})
+}
@@ -0,0 +1,333 @@
+package user
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// songRow is a song joined with its group name, Score is set in fuzzy mode only.
+type songRow struct {
+	ID          int
+	GroupID     int
+	GroupName   string
+	Song        string
+	Text        string
+	ReleaseDate string
+	Link        string
+	Score       float64
+	Position    int
+	Version     int
+
+	EnrichmentStatus string
+	Sources          map[string]string `gorm:"serializer:json"`
+}
+
+func (r songRow) toModel() model.Song {
+	return model.Song{
+		ID:          r.ID,
+		GroupID:     r.GroupID,
+		Group:       r.GroupName,
+		Song:        r.Song,
+		Text:        r.Text,
+		ReleaseDate: r.ReleaseDate,
+		Link:        r.Link,
+		Score:       r.Score,
+		Version:     r.Version,
+
+		EnrichmentStatus: r.EnrichmentStatus,
+		Sources:          r.Sources,
+	}
+}
+
+const songRowColumns = `songs.id, songs.group_id, groups.name as group_name, songs.song,
+	coalesce(songs.song_text, '') as text, coalesce(songs.release_date, '') as release_date,
+	coalesce(songs.link, '') as link, songs.version, songs.enrichment_status,
+	songs.detail_sources as sources`
+
+// orderKey is one ORDER BY expression of the listing together with the way to read
+// its value from a row, which is needed to build a keyset cursor.
+type orderKey struct {
+	expr  string
+	args  []interface{}
+	desc  bool
+	value func(songRow) interface{}
+}
+
+// sortKeys maps sort fields of the listing to order keys.
+var sortKeys = map[string]orderKey{
+	"group":        {expr: "groups.name", value: func(r songRow) interface{} { return r.GroupName }},
+	"song":         {expr: "songs.song", value: func(r songRow) interface{} { return r.Song }},
+	"release_date": {expr: "coalesce(songs.release_date, '')", value: func(r songRow) interface{} { return r.ReleaseDate }},
+	"id":           {expr: "songs.id", value: func(r songRow) interface{} { return r.ID }},
+}
+
+// cursor is the decoded form of the opaque next_cursor value: the order key values
+// of the last returned row and a hash of the order it was built for.
+type cursor struct {
+	Values []interface{} `json:"v"`
+	Order  uint32        `json:"o"`
+}
+
+func orderHash(keys []orderKey) uint32 {
+	h := fnv.New32a()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s %v %t;", key.expr, key.args, key.desc)
+	}
+	return h.Sum32()
+}
+
+func encodeCursor(keys []orderKey, row songRow) (string, error) {
+	c := cursor{Order: orderHash(keys)}
+	for _, key := range keys {
+		c.Values = append(c.Values, key.value(row))
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(keys []orderKey, value string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor{}, apperror.Validation("invalid cursor")
+	}
+	if err := json.Unmarshal(data, &c); err != nil || len(c.Values) != len(keys) {
+		return cursor{}, apperror.Validation("invalid cursor")
+	}
+	if c.Order != orderHash(keys) {
+		return cursor{}, apperror.Validation("cursor was issued for another sort order")
+	}
+	return c, nil
+}
+
+// keysetCondition returns a condition selecting rows strictly after the cursor:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., with < for descending keys.
+func keysetCondition(keys []orderKey, c cursor) (string, []interface{}) {
+	var (
+		alternatives []string
+		args         []interface{}
+	)
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("(%s) = ?", keys[j].expr))
+			args = append(args, keys[j].args...)
+			args = append(args, c.Values[j])
+		}
+		operator := ">"
+		if key.desc {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("(%s) %s ?", key.expr, operator))
+		args = append(args, key.args...)
+		args = append(args, c.Values[i])
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return strings.Join(alternatives, " OR "), args
+}
+
+// songQuery is the filtered listing shared by GetSongsInfo and ExportSongs.
+type songQuery struct {
+	query       *gorm.DB
+	columns     string
+	columnArgs  []interface{}
+	defaultKeys []orderKey
+}
+
+// buildSongQuery applies the filters, it has to run in a transaction because fuzzy mode sets the similarity threshold for it.
+func buildSongQuery(tx *gorm.DB, filters model.SongFilters) (songQuery, error) {
+	fuzzy := filters.Match == model.MatchFuzzy && (filters.Group != "" || filters.Song != "")
+	query := tx.Table("songs").
+		Joins("JOIN groups ON groups.id = songs.group_id").
+		Where("songs.deleted_at IS NULL")
+	result := songQuery{columns: songRowColumns}
+	if fuzzy {
+		// The % operator uses the trigram indexes and compares with this threshold.
+		if err := tx.Exec("select set_config('pg_trgm.similarity_threshold', ?, true)",
+			strconv.FormatFloat(*filters.Threshold, 'f', -1, 64)).Error; err != nil {
+			return songQuery{}, err
+		}
+		score := orderKey{desc: true, value: func(r songRow) interface{} { return r.Score }}
+		var scores []string
+		if group := filters.Group; group != "" {
+			query = query.Where("groups.name % ?", group)
+			scores = append(scores, "similarity(groups.name, ?)")
+			score.args = append(score.args, group)
+		}
+		if song := filters.Song; song != "" {
+			query = query.Where("songs.song % ?", song)
+			scores = append(scores, "similarity(songs.song, ?)")
+			score.args = append(score.args, song)
+		}
+		score.expr = fmt.Sprintf("((%s) / %d)::float8", strings.Join(scores, " + "), len(scores))
+		result.columns += ", " + score.expr + " as score"
+		result.columnArgs = score.args
+		result.defaultKeys = append(result.defaultKeys, score)
+	} else {
+		if group := filters.Group; group != "" {
+			query = query.Where("lower(groups.name) like lower(?)", "%"+group+"%")
+		}
+		if song := filters.Song; song != "" {
+			query = query.Where("songs.song LIKE ?", "%"+song+"%")
+		}
+	}
+	if groupID := filters.GroupID; groupID != 0 {
+		query = query.Where("songs.group_id = ?", groupID)
+	}
+	if text := filters.Text; text != "" {
+		query = query.Where(lyricsVector+" @@ plainto_tsquery(?, ?)", searchConfig, text)
+	}
+	if releaseDate := filters.ReleaseDate; releaseDate != "" {
+		query = query.Where("songs.release_date LIKE ?", "%"+releaseDate+"%")
+	}
+	if link := filters.Link; link != "" {
+		query = query.Where("songs.link LIKE ?", "%"+link+"%")
+	}
+	if albumID := filters.AlbumID; albumID != 0 {
+		query = query.Joins("JOIN album_tracks ON album_tracks.song_id = songs.id AND album_tracks.album_id = ?", albumID)
+		result.columns += ", album_tracks.position"
+		result.defaultKeys = append(result.defaultKeys, orderKey{
+			expr:  "album_tracks.position",
+			value: func(r songRow) interface{} { return r.Position },
+		})
+	}
+	result.query = query.Session(&gorm.Session{})
+	return result, nil
+}
+
+// GetSongsInfo returns a page of songs. With page > 0 the page is selected by offset,
+// with page == 0 it starts after filters.Cursor (from the beginning when it is empty)
+// and NextCursor is set while there are more songs.
+func (s *store) GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) (model.SongPage, error) {
+	result := model.SongPage{
+		Items:    []model.Song{},
+		Page:     page,
+		PageSize: pageSize,
+	}
+	var rows []songRow
+	var keys []orderKey
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		songs, err := buildSongQuery(tx, filters)
+		if err != nil {
+			return err
+		}
+		if err := songs.query.Count(&result.Total).Error; err != nil {
+			return err
+		}
+
+		keys = orderKeys(filters.Sort, songs.defaultKeys)
+		query := songs.query.Select(songs.columns, songs.columnArgs...)
+		query = query.Order(orderBy(keys))
+		if page > 0 {
+			return query.
+				Offset((page - 1) * pageSize).
+				Limit(pageSize).
+				Scan(&rows).Error
+		}
+		if filters.Cursor != "" {
+			after, err := decodeCursor(keys, filters.Cursor)
+			if err != nil {
+				return err
+			}
+			condition, args := keysetCondition(keys, after)
+			query = query.Where(condition, args...)
+		}
+		return query.
+			Limit(pageSize + 1).
+			Scan(&rows).Error
+	})
+	if err != nil {
+		return model.SongPage{}, dberr.TranslateError(err, entity)
+	}
+	if page == 0 && len(rows) > pageSize {
+		rows = rows[:pageSize]
+		next, err := encodeCursor(keys, rows[len(rows)-1])
+		if err != nil {
+			return model.SongPage{}, err
+		}
+		result.NextCursor = next
+	}
+	for _, row := range rows {
+		result.Items = append(result.Items, row.toModel())
+	}
+	return result, nil
+}
+
+// ExportSongs passes every song matching the filters to fn in the listing order. Rows are read
+// from the database one by one, so the whole library is never held in memory. An error from fn stops the export.
+func (s *store) ExportSongs(ctx context.Context, filters model.SongFilters, fn func(model.Song) error) error {
+	return dberr.TranslateError(s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		songs, err := buildSongQuery(tx, filters)
+		if err != nil {
+			return err
+		}
+		rows, err := songs.query.
+			Select(songs.columns, songs.columnArgs...).
+			Order(orderBy(orderKeys(filters.Sort, songs.defaultKeys))).
+			Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var row songRow
+			if err := tx.ScanRows(rows, &row); err != nil {
+				return err
+			}
+			if err := fn(row.toModel()); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}), entity)
+}
+
+func orderBy(keys []orderKey) clause.OrderBy {
+	var (
+		parts []string
+		args  []interface{}
+	)
+	for _, key := range keys {
+		direction := " asc"
+		if key.desc {
+			direction = " desc"
+		}
+		parts = append(parts, key.expr+direction)
+		args = append(args, key.args...)
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(parts, ", "), Vars: args}}
+}
+
+// orderKeys returns the requested sort keys or the default ones,
+// songs.id is always the last key so the order is total and pages are stable.
+func orderKeys(sort []model.SortKey, defaultKeys []orderKey) []orderKey {
+	keys := defaultKeys
+	if len(sort) > 0 {
+		keys = nil
+		for _, s := range sort {
+			key := sortKeys[s.Field]
+			key.desc = s.Desc
+			keys = append(keys, key)
+		}
+	}
+	for _, s := range sort {
+		if s.Field == "id" {
+			return keys
+		}
+	}
+	return append(keys, sortKeys["id"])
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+)
+
+func TestCursor(t *testing.T) {
+	groupDesc := sortKeys["group"]
+	groupDesc.desc = true
+	keys := []orderKey{groupDesc, sortKeys["id"]}
+	row := songRow{ID: 7, GroupName: "Muse", Song: "Uprising"}
+
+	value, err := encodeCursor(keys, row)
+	if err != nil {
+		t.Fatalf("encodeCursor() error = %v", err)
+	}
+	got, err := decodeCursor(keys, value)
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	// The values come back as JSON types.
+	if want := []interface{}{"Muse", float64(7)}; !reflect.DeepEqual(got.Values, want) {
+		t.Errorf("decodeCursor() values = %v, want %v", got.Values, want)
+	}
+
+	tests := []struct {
+		name    string
+		keys    []orderKey
+		value   string
+		wantErr string
+	}{
+		{name: "not base64", keys: keys, value: "!!!", wantErr: "invalid cursor"},
+		{name: "not json", keys: keys, value: base64.RawURLEncoding.EncodeToString([]byte("{v:")), wantErr: "invalid cursor"},
+		{
+			name:    "values removed by the client",
+			keys:    keys,
+			value:   base64.RawURLEncoding.EncodeToString([]byte(`{"v":["Muse"],"o":1}`)),
+			wantErr: "invalid cursor",
+		},
+		{name: "other sort direction", keys: []orderKey{sortKeys["group"], sortKeys["id"]}, value: value, wantErr: "another sort order"},
+		{name: "other sort field", keys: []orderKey{sortKeys["song"], sortKeys["id"]}, value: value, wantErr: "another sort order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.keys, tt.value)
+			if err == nil || !errors.Is(err, apperror.ErrValidation) {
+				t.Fatalf("decodeCursor() error = %v, want a validation error", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("decodeCursor() error = %q, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	groupDesc := sortKeys["group"]
+	groupDesc.desc = true
+	condition, args := keysetCondition([]orderKey{groupDesc, sortKeys["id"]}, cursor{Values: []interface{}{"Muse", 7}})
+	wantCondition := "((groups.name) < ?) OR ((groups.name) = ? AND (songs.id) > ?)"
+	if condition != wantCondition {
+		t.Errorf("keysetCondition() = %q, want %q", condition, wantCondition)
+	}
+	if want := []interface{}{"Muse", "Muse", 7}; !reflect.DeepEqual(args, want) {
+		t.Errorf("keysetCondition() args = %v, want %v", args, want)
+	}
+}
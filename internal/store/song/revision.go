@@ -0,0 +1,169 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/audit"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const revisionEntity = "song revision"
+
+// lockSong locks the song row until the end of the transaction, so revisions of a song are numbered without gaps.
+var lockSong = clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "songs"}}
+
+func findSong(tx *gorm.DB, id int) (core.Song, error) {
+	var song core.Song
+	if err := tx.Joins("Group").Where("songs.id = ?", id).First(&song).Error; err != nil {
+		return core.Song{}, dberr.TranslateError(err, entity)
+	}
+	return song, nil
+}
+
+// writeRevision appends the next revision of the song. before is nil when the song did not exist
+// before the change and after is nil when it was deleted.
+func writeRevision(ctx context.Context, tx *gorm.DB, songID int, action string, before, after *core.Song) error {
+	revision, err := newRevision(ctx, songID, action, before, after)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&core.SongRevision{}).
+		Where("song_id = ?", songID).
+		Select("coalesce(max(revision), 0) + 1").
+		Scan(&revision.Revision).Error; err != nil {
+		return dberr.TranslateError(err, revisionEntity)
+	}
+	return dberr.TranslateError(tx.Create(&revision).Error, revisionEntity)
+}
+
+func newRevision(ctx context.Context, songID int, action string, before, after *core.Song) (core.SongRevision, error) {
+	revision := core.SongRevision{
+		SongID:    songID,
+		Action:    action,
+		Actor:     audit.Actor(ctx),
+		RequestID: audit.RequestID(ctx),
+	}
+	var err error
+	if revision.OldData, err = encodeSnapshot(before); err != nil {
+		return core.SongRevision{}, err
+	}
+	if revision.NewData, err = encodeSnapshot(after); err != nil {
+		return core.SongRevision{}, err
+	}
+	return revision, nil
+}
+
+func encodeSnapshot(song *core.Song) (*string, error) {
+	if song == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(song.Snapshot())
+	if err != nil {
+		return nil, err
+	}
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+func (s *store) GetSongHistory(ctx context.Context, id, page, pageSize int) ([]model.SongRevision, error) {
+	query := s.DB.WithContext(ctx).Model(&core.SongRevision{}).Where("song_id = ?", id)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return []model.SongRevision{}, dberr.TranslateError(err, revisionEntity)
+	}
+	if total == 0 {
+		// Songs created before the history was recorded have no revisions, songs in the trash have a history too.
+		if _, err := findSong(s.DB.WithContext(ctx).Unscoped(), id); err != nil {
+			return []model.SongRevision{}, err
+		}
+		return []model.SongRevision{}, nil
+	}
+	var revisions []core.SongRevision
+	if err := s.DB.WithContext(ctx).
+		Where("song_id = ?", id).
+		Order("revision desc").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&revisions).Error; err != nil {
+		return []model.SongRevision{}, dberr.TranslateError(err, revisionEntity)
+	}
+	history := []model.SongRevision{}
+	for _, revision := range revisions {
+		item, err := revision.ToModel()
+		if err != nil {
+			return []model.SongRevision{}, err
+		}
+		history = append(history, item)
+	}
+	return history, nil
+}
+
+// RevertSong restores the fields of the song to their state right after the given revision and records it as a new revision.
+func (s *store) RevertSong(ctx context.Context, id, revision int) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), id)
+		if err != nil {
+			return err
+		}
+		var target core.SongRevision
+		if err := tx.Where("song_id = ? AND revision = ?", id, revision).First(&target).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFound("revision %d of song %d not found", revision, id)
+			}
+			return dberr.TranslateError(err, revisionEntity)
+		}
+		state, err := target.ToModel()
+		if err != nil {
+			return err
+		}
+		if state.New == nil {
+			return apperror.Validation("revision %d deleted the song, there is nothing to revert to", revision)
+		}
+		changes := map[string]any{
+			"group_id":     state.New.GroupID,
+			"song":         state.New.Song,
+			"song_text":    nullIfEmpty(state.New.Text),
+			"release_date": nullIfEmpty(state.New.ReleaseDate),
+			"link":         nullIfEmpty(state.New.Link),
+
+			"detail_sources": sourcesColumn(state.New.Sources),
+		}
+		setLyrics(changes)
+		err = tx.Model(&core.Song{}).Where("id = ?", id).Updates(changes).Error
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return apperror.Validation("group %d of revision %d no longer exists", state.New.GroupID, revision)
+		}
+		if err != nil {
+			return dberr.TranslateError(err, entity)
+		}
+		after, err := findSong(tx, id)
+		if err != nil {
+			return err
+		}
+		return writeRevision(ctx, tx, id, model.RevisionRevert, &before, &after)
+	})
+}
+
+// sourcesColumn encodes the provenance of details for Updates, songs without sources get NULL.
+func sourcesColumn(sources map[string]string) any {
+	if len(sources) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(sources)
+	return string(data)
+}
+
+// nullIfEmpty restores cleared optional columns as NULL, snapshots keep them as empty strings.
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
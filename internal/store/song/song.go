@@ -2,11 +2,31 @@ package user
 
 import (
 	"context"
-	"strings"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"time"
 
+	"github.com/kleo-53/music-system/internal/apperror"
 	"github.com/kleo-53/music-system/internal/controller/model"
 	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/lyrics"
+	"github.com/kleo-53/music-system/internal/store/dberr"
 	"github.com/kleo-53/music-system/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	entity = "song"
+
+	// searchConfig is the text search configuration of songs.search_vector, see migration 000004.
+	searchConfig = "simple"
+	// lyricsVector is the text search vector of the lyrics alone, it is indexed by migration 000004.
+	lyricsVector    = "to_tsvector('simple'::regconfig, coalesce(songs.song_text, ''))"
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=20, MinWords=5, FragmentDelimiter=\" … \""
 )
 
 type store struct {
@@ -17,20 +37,12 @@ func New(pg *postgres.Postgres) core.SongStore {
 	return &store{pg}
 }
 
-func convertToModelSong(song core.Song) model.Song {
-	return model.Song{
-		Song:        song.Song,
-		Group:       song.Group,
-		Text:        song.Text,
-		ReleaseDate: song.ReleaseDate,
-		Link:        song.Link,
-	}
-}
-
-func (s *store) CreateSong(ctx context.Context, song model.SongCommon, details model.SongDetail) error {
+func (s *store) CreateSong(ctx context.Context, groupID int, song model.SongCommon, details model.SongDetail, status string) (core.Song, error) {
 	songToAdd := core.Song{
-		Group: song.Group,
-		Song:  song.Song,
+		GroupID:          groupID,
+		Song:             song.Song,
+		EnrichmentStatus: status,
+		DetailSources:    details.Sources,
 	}
 	if details.Text != "" {
 		songToAdd.Text = details.Text
@@ -41,92 +53,313 @@ func (s *store) CreateSong(ctx context.Context, song model.SongCommon, details m
 	if details.Link != "" {
 		songToAdd.Link = details.Link
 	}
-	return s.DB.WithContext(ctx).Create(&songToAdd).Error
+	songToAdd.Lyrics, songToAdd.LyricsVersion = lyrics.Parse(songToAdd.Text), lyrics.Version
+	var created core.Song
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&songToAdd).Error; err != nil {
+			if errors.Is(err, gorm.ErrForeignKeyViolated) {
+				return apperror.Validation("group %d does not exist", groupID)
+			}
+			return dberr.TranslateError(err, entity)
+		}
+		if err := enqueueJobs(tx, []core.Song{songToAdd}); err != nil {
+			return err
+		}
+		var err error
+		if created, err = findSong(tx, songToAdd.ID); err != nil {
+			return err
+		}
+		return writeRevision(ctx, tx, created.ID, model.RevisionCreate, nil, &created)
+	})
+	if err != nil {
+		return core.Song{}, err
+	}
+	return created, nil
 }
 
-func (s *store) UpdateSong(ctx context.Context, id int, newData model.SongFilters) error {
-	var err error
-	if newData.Text != "" {
-		err = s.DB.WithContext(ctx).Model(&core.Song{}).Where("id = ?", id).Update("song_text", &newData.Text).Error
+// UpdateSong applies the patch and records the revision in one transaction, groupID replaces the group when it is not 0.
+func (s *store) UpdateSong(ctx context.Context, id, groupID int, patch model.SongPatch, versions []int) (core.Song, error) {
+	changes := map[string]any{}
+	if groupID != 0 {
+		changes["group_id"] = groupID
+	}
+	patchColumn(changes, "song", patch.Song)
+	patchColumn(changes, "song_text", patch.Text)
+	setLyrics(changes)
+	patchColumn(changes, "release_date", patch.ReleaseDate)
+	patchColumn(changes, "link", patch.Link)
+	var updated core.Song
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), id)
 		if err != nil {
 			return err
 		}
-	}
-	if newData.Link != "" {
-		err = s.DB.WithContext(ctx).Model(&core.Song{}).Where("id = ?", id).Update("link", &newData.Link).Error
-		if err != nil {
+		if err := checkVersion(before, versions); err != nil {
 			return err
 		}
+		if sources, ok := patchSources(before.DetailSources, patch); ok {
+			changes["detail_sources"] = sourcesColumn(sources)
+		}
+		if len(changes) == 0 {
+			updated = before
+			return nil
+		}
+		if err := tx.Model(&core.Song{}).Where("id = ?", id).Updates(changes).Error; err != nil {
+			return dberr.TranslateError(err, entity)
+		}
+		if updated, err = findSong(tx, id); err != nil {
+			return err
+		}
+		return writeRevision(ctx, tx, id, model.RevisionUpdate, &before, &updated)
+	})
+	if err != nil {
+		return core.Song{}, err
 	}
-	if newData.ReleaseDate != "" {
-		err = s.DB.WithContext(ctx).Model(&core.Song{}).Where("id = ?", id).Update("release_date", &newData.ReleaseDate).Error
+	return updated, nil
+}
+
+// checkVersion compares the locked song with the versions the client expects, nil means any version.
+func checkVersion(song core.Song, versions []int) error {
+	if versions == nil || slices.Contains(versions, song.Version) {
+		return nil
+	}
+	return apperror.PreconditionFailed("song %d was changed, its current version is %d", song.ID, song.Version)
+}
+
+// setLyrics adds the parsed sections when the changes set song_text.
+func setLyrics(changes map[string]any) {
+	text, ok := changes["song_text"]
+	if !ok {
+		return
+	}
+	value, _ := text.(string)
+	changes["lyrics"] = lyricsColumn(lyrics.Parse(value))
+	changes["lyrics_version"] = lyrics.Version
+}
+
+// lyricsColumn encodes sections for Updates, songs without text get NULL.
+func lyricsColumn(sections []model.LyricsSection) any {
+	if len(sections) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(sections)
+	return string(data)
+}
+
+// patchSources forgets the providers of details set by the patch, ok is false when nothing changes.
+func patchSources(sources map[string]string, patch model.SongPatch) (map[string]string, bool) {
+	patched := map[string]bool{
+		model.DetailText:        patch.Text.Set,
+		model.DetailReleaseDate: patch.ReleaseDate.Set,
+		model.DetailLink:        patch.Link.Set,
+	}
+	result := maps.Clone(sources)
+	maps.DeleteFunc(result, func(detail, _ string) bool {
+		return patched[detail]
+	})
+	return result, len(result) != len(sources)
+}
+
+// patchColumn adds the column to changes when the field is present in the patch, null clears the column.
+func patchColumn(changes map[string]any, column string, field model.Nullable[string]) {
+	switch {
+	case !field.Set:
+	case field.Null:
+		changes[column] = nil
+	default:
+		changes[column] = field.Value
+	}
+}
+
+func (s *store) DeleteSong(ctx context.Context, id int, versions []int) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), id)
 		if err != nil {
 			return err
 		}
-	}
-	if newData.Song != "" {
-		err = s.DB.WithContext(ctx).Model(&core.Song{}).Where("id = ?", id).Update("song", &newData.Song).Error
+		if err := checkVersion(before, versions); err != nil {
+			return err
+		}
+		if err := dberr.RequireAffected(tx.Delete(&core.Song{}, "id = ?", id), entity); err != nil {
+			return err
+		}
+		return writeRevision(ctx, tx, id, model.RevisionDelete, &before, nil)
+	})
+}
+
+func (s *store) RestoreSong(ctx context.Context, id int) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := dberr.RequireAffected(tx.
+			Unscoped().
+			Model(&core.Song{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil), entity)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return apperror.NotFound("song %d is not in the trash", id)
+		}
 		if err != nil {
 			return err
 		}
-	}
-	if newData.Group != "" {
-		err = s.DB.WithContext(ctx).Model(&core.Song{}).Where("id = ?", id).Update("song_group", &newData.Group).Error
+		after, err := findSong(tx, id)
 		if err != nil {
 			return err
 		}
+		return writeRevision(ctx, tx, id, model.RevisionRestore, nil, &after)
+	})
+}
+
+// PurgeSongs permanently removes songs moved to the trash before deletedBefore, their history keeps a purge revision.
+func (s *store) PurgeSongs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var songs []core.Song
+		if err := tx.Unscoped().
+			Clauses(lockSong).
+			Joins("Group").
+			Where("songs.deleted_at IS NOT NULL AND songs.deleted_at < ?", deletedBefore).
+			Find(&songs).Error; err != nil {
+			return dberr.TranslateError(err, entity)
+		}
+		for i := range songs {
+			if err := tx.Unscoped().Delete(&core.Song{}, songs[i].ID).Error; err != nil {
+				return dberr.TranslateError(err, entity)
+			}
+			if err := writeRevision(ctx, tx, songs[i].ID, model.RevisionPurge, &songs[i], nil); err != nil {
+				return err
+			}
+		}
+		purged = int64(len(songs))
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return purged, nil
 }
 
-func (s *store) DeleteSong(ctx context.Context, id int) error {
-	return s.DB.WithContext(ctx).Delete(&core.Song{}, "id = ?", id).Error
+func (s *store) GetTrash(ctx context.Context, page, pageSize int) (model.SongPage, error) {
+	result := model.SongPage{
+		Items:    []model.Song{},
+		Page:     page,
+		PageSize: pageSize,
+	}
+	query := s.DB.WithContext(ctx).
+		Unscoped().
+		Model(&core.Song{}).
+		Where("songs.deleted_at IS NOT NULL").
+		Session(&gorm.Session{})
+	if err := query.Count(&result.Total).Error; err != nil {
+		return model.SongPage{}, dberr.TranslateError(err, entity)
+	}
+	var songs []core.Song
+	if err := query.
+		Joins("Group").
+		Order("songs.deleted_at desc, songs.id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&songs).Error; err != nil {
+		return model.SongPage{}, dberr.TranslateError(err, entity)
+	}
+	for _, song := range songs {
+		result.Items = append(result.Items, song.ToModel())
+	}
+	return result, nil
 }
 
-func (s *store) GetSongText(ctx context.Context, id, page, pageSize int) ([]string, error) {
+func (s *store) GetSong(ctx context.Context, id int) (model.Song, error) {
 	var song core.Song
 	if err := s.DB.WithContext(ctx).
-		Model(core.Song{}).
-		Where("id = ?", id).
+		Joins("Group").
+		Where("songs.id = ?", id).
 		First(&song).Error; err != nil {
-		return []string{}, err
-	}
-	couplets := strings.Split(song.Text, "\n\n")
-	start := (page - 1) * pageSize
-	end := min(len(couplets), start+pageSize)
-	if start > len(couplets) {
-		return []string{}, nil
+		return model.Song{}, dberr.TranslateError(err, entity)
 	}
-	return couplets[start:end], nil
+	return song.ToModel(), nil
 }
 
-func (s *store) GetSongsInfo(ctx context.Context, filters model.SongFilters, page, pageSize int) ([]model.Song, error) {
-	var songs []core.Song
-	query := s.DB.WithContext(ctx).Model(&core.Song{})
-	if group := filters.Group; group != "" {
-		query = query.Where("song_group like ?", "%"+group+"%")
+// GetLyrics returns the stored sections. The text of songs added before the sections were stored or parsed
+// by an older lyrics.Version is parsed on read, writing the result back would give the song a new version.
+func (s *store) GetLyrics(ctx context.Context, id int) (model.Lyrics, error) {
+	var song core.Song
+	if err := s.DB.WithContext(ctx).
+		Select("id", "song_text", "lyrics", "lyrics_version").
+		Where("id = ?", id).
+		First(&song).Error; err != nil {
+		return model.Lyrics{}, dberr.TranslateError(err, entity)
 	}
-	if song := filters.Song; song != "" {
-		query = query.Where("song LIKE ?", "%"+song+"%")
+	sections := song.Lyrics
+	if sections == nil || song.LyricsVersion != lyrics.Version {
+		sections = lyrics.Parse(song.Text)
 	}
-	if text := filters.Text; text != "" {
-		query = query.Where("song_text LIKE ?", "%"+text+"%")
+	if sections == nil {
+		sections = []model.LyricsSection{}
 	}
-	if releaseDate := filters.ReleaseDate; releaseDate != "" {
-		query = query.Where("release_date LIKE ?", "%"+releaseDate+"%")
+	return model.Lyrics{SongID: song.ID, Sections: sections}, nil
+}
+
+// GetSongText returns the song text, or its version in lang when lang is set.
+func (s *store) GetSongText(ctx context.Context, id int, lang string) (string, error) {
+	if lang != "" {
+		version, err := s.GetSongLyrics(ctx, id, lang)
+		if err != nil {
+			return "", err
+		}
+		return version.Text, nil
 	}
-	if link := filters.Link; link != "" {
-		query = query.Where("link LIKE ?", "%"+link+"%")
+	var song core.Song
+	if err := s.DB.WithContext(ctx).
+		Model(core.Song{}).
+		Where("id = ?", id).
+		First(&song).Error; err != nil {
+		return "", dberr.TranslateError(err, entity)
 	}
-	if err := query.
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&songs).Error; err != nil {
-		return []model.Song{}, err
+	return song.Text, nil
+}
+
+type searchRow struct {
+	ID          int
+	GroupID     int
+	GroupName   string
+	Song        string
+	ReleaseDate string
+	Link        string
+	Rank        float64
+	Headline    string
+}
+
+func (s *store) SearchSongs(ctx context.Context, tsQuery string, page, pageSize int) ([]model.SongSearchResult, error) {
+	var rows []searchRow
+	if err := s.DB.WithContext(ctx).Raw(`
+		select songs.id, songs.group_id, groups.name as group_name, songs.song,
+			coalesce(songs.release_date, '') as release_date, coalesce(songs.link, '') as link,
+			ts_rank(songs.search_vector, query) as rank,
+			ts_headline(@config, coalesce(songs.song_text, ''), query, @options) as headline
+		from songs
+			join groups on groups.id = songs.group_id,
+			to_tsquery(@config, @query) query
+		where songs.search_vector @@ query and songs.deleted_at is null
+		order by rank desc, songs.id
+		limit @limit offset @offset`,
+		sql.Named("config", searchConfig),
+		sql.Named("options", headlineOptions),
+		sql.Named("query", tsQuery),
+		sql.Named("limit", pageSize),
+		sql.Named("offset", (page-1)*pageSize),
+	).Scan(&rows).Error; err != nil {
+		return []model.SongSearchResult{}, dberr.TranslateError(err, entity)
 	}
-	responce := []model.Song{}
-	for _, song := range songs {
-		responce = append(responce, convertToModelSong(song))
+	responce := []model.SongSearchResult{}
+	for _, row := range rows {
+		responce = append(responce, model.SongSearchResult{
+			ID:          row.ID,
+			GroupID:     row.GroupID,
+			Group:       row.GroupName,
+			Song:        row.Song,
+			ReleaseDate: row.ReleaseDate,
+			Link:        row.Link,
+			Rank:        row.Rank,
+			Headline:    row.Headline,
+		})
 	}
 	return responce, nil
 }
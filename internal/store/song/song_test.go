@@ -0,0 +1,31 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/core"
+)
+
+func TestCheckVersion(t *testing.T) {
+	song := core.Song{ID: 1, Version: 3}
+	tests := []struct {
+		name     string
+		versions []int
+		wantErr  bool
+	}{
+		{name: "any version", versions: nil},
+		{name: "current version", versions: []int{3}},
+		{name: "one of the versions", versions: []int{1, 3}},
+		{name: "old version", versions: []int{2}, wantErr: true},
+		{name: "no strong tags", versions: []int{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVersion(song, tt.versions)
+			if tt.wantErr != (apperror.KindOf(err) == apperror.KindPrecondition) || !tt.wantErr && err != nil {
+				t.Errorf("checkVersion(%v) error = %v, want precondition failure %v", tt.versions, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const syncedEntity = "synced lyrics"
+
+// PutSyncedLyrics replaces the synced lyrics of the song, songs in the trash are not found.
+func (s *store) PutSyncedLyrics(ctx context.Context, id int, source string, lyrics model.SyncedLyrics) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findSong(tx.Clauses(lockSong), id); err != nil {
+			return err
+		}
+		synced := core.SyncedLyrics{SongID: id, Source: source, Data: lyrics}
+		return dberr.TranslateError(tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "song_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"source":     gorm.Expr("excluded.source"),
+				"data":       gorm.Expr("excluded.data"),
+				"updated_at": gorm.Expr("now()"),
+			}),
+		}).Create(&synced).Error, syncedEntity)
+	})
+}
+
+func (s *store) GetSyncedLyrics(ctx context.Context, id int) (core.SyncedLyrics, error) {
+	var synced core.SyncedLyrics
+	err := s.DB.WithContext(ctx).
+		Joins("JOIN songs ON songs.id = song_synced_lyrics.song_id AND songs.deleted_at IS NULL").
+		Where("song_synced_lyrics.song_id = ?", id).
+		First(&synced).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return core.SyncedLyrics{}, apperror.NotFound("song %d has no synced lyrics", id)
+	}
+	if err != nil {
+		return core.SyncedLyrics{}, dberr.TranslateError(err, syncedEntity)
+	}
+	synced.Data.SongID = synced.SongID
+	return synced, nil
+}
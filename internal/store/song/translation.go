@@ -0,0 +1,158 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/kleo-53/music-system/internal/apperror"
+	"github.com/kleo-53/music-system/internal/controller/model"
+	"github.com/kleo-53/music-system/internal/core"
+	"github.com/kleo-53/music-system/internal/lyrics"
+	"github.com/kleo-53/music-system/internal/store/dberr"
+	"gorm.io/gorm"
+)
+
+const translationEntity = "song lyrics"
+
+// versionColumns select a language version with the text of the song for the original.
+const versionColumns = "song_lyrics.song_id, song_lyrics.lang, song_lyrics.original, " +
+	"coalesce(song_lyrics.text, songs.song_text, '') as text, song_lyrics.detected, song_lyrics.updated_at"
+
+// PutSongLyrics adds or replaces the version of the song text in version.Lang. An original with a new
+// text replaces the text of the song and the previous original is kept as a translation of it, also when
+// its language was never stored. Without a new text the original only changes its language.
+func (s *store) PutSongLyrics(ctx context.Context, id int, version core.SongLyrics) (core.SongLyrics, error) {
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := findSong(tx.Clauses(lockSong), id)
+		if err != nil {
+			return err
+		}
+		var current core.SongLyrics
+		err = tx.Where("song_id = ? AND lang = ?", id, version.Lang).Take(&current).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dberr.TranslateError(err, translationEntity)
+		}
+		if !version.Original {
+			if current.Original {
+				return apperror.Conflict("%s is the original language of song %d", version.Lang, id)
+			}
+			return upsertSongLyrics(tx, id, version, version.Text)
+		}
+		previous := tx.Where("song_id = ? AND original AND lang <> ?", id, version.Lang)
+		if version.Text == "" || version.Text == before.Text {
+			// Only the language of the original changes, the previous one labelled the same text.
+			if err := previous.Delete(&core.SongLyrics{}).Error; err != nil {
+				return dberr.TranslateError(err, translationEntity)
+			}
+			return upsertSongLyrics(tx, id, version, nil)
+		}
+		changes := map[string]any{"song_text": version.Text}
+		setLyrics(changes)
+		if err := tx.Model(&core.Song{}).Where("id = ?", id).Updates(changes).Error; err != nil {
+			return dberr.TranslateError(err, entity)
+		}
+		after, err := findSong(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := writeRevision(ctx, tx, id, model.RevisionUpdate, &before, &after); err != nil {
+			return err
+		}
+		kept := previous.Model(&core.SongLyrics{}).
+			Updates(map[string]any{"original": false, "text": before.Text, "updated_at": gorm.Expr("now()")})
+		if kept.Error != nil {
+			return dberr.TranslateError(kept.Error, translationEntity)
+		}
+		if kept.RowsAffected == 0 && before.Text != "" {
+			if err := keepUnlabelledOriginal(tx, id, version.Lang, before.Text); err != nil {
+				return err
+			}
+		}
+		return upsertSongLyrics(tx, id, version, nil)
+	})
+	if err != nil {
+		return core.SongLyrics{}, err
+	}
+	return s.getSongLyrics(s.DB.WithContext(ctx), id, version.Lang)
+}
+
+// keepUnlabelledOriginal stores the text of an original without a language as a translation. It gets the
+// detected language or lyrics.Undetermined when that one is unknown or already taken by another version.
+func keepUnlabelledOriginal(tx *gorm.DB, id int, lang, text string) error {
+	var taken []string
+	if err := tx.Model(&core.SongLyrics{}).Where("song_id = ?", id).Pluck("lang", &taken).Error; err != nil {
+		return dberr.TranslateError(err, translationEntity)
+	}
+	previous, detected := unlabelledLanguage(text, append(taken, lang))
+	if previous == "" {
+		return apperror.Conflict("song %d already has a version in %q, the previous text cannot be kept", id, lyrics.Undetermined)
+	}
+	return upsertSongLyrics(tx, id, core.SongLyrics{Lang: previous, Detected: detected}, text)
+}
+
+// unlabelledLanguage picks a language not in taken for the text of an original without a language,
+// it is empty when even lyrics.Undetermined is taken.
+func unlabelledLanguage(text string, taken []string) (lang string, detected bool) {
+	if lang, ok := lyrics.DetectLanguage(text); ok && !slices.Contains(taken, lang) {
+		return lang, true
+	}
+	if slices.Contains(taken, lyrics.Undetermined) {
+		return "", false
+	}
+	return lyrics.Undetermined, false
+}
+
+// upsertSongLyrics writes the version, text is nil for the original.
+func upsertSongLyrics(tx *gorm.DB, id int, version core.SongLyrics, text any) error {
+	err := tx.Exec(`
+		insert into song_lyrics (song_id, lang, original, text, detected)
+		values (?, ?, ?, ?, ?)
+		on conflict (song_id, lang) do update
+		set original = excluded.original, text = excluded.text, detected = excluded.detected, updated_at = now()`,
+		id, version.Lang, version.Original, text, version.Detected).Error
+	return dberr.TranslateError(err, translationEntity)
+}
+
+// ListSongLyrics returns the original first and then the translations by language. When the language
+// of the original is not stored, the song text is returned as the original with an empty Lang.
+func (s *store) ListSongLyrics(ctx context.Context, id int) ([]core.SongLyrics, error) {
+	db := s.DB.WithContext(ctx)
+	song, err := findSong(db, id)
+	if err != nil {
+		return []core.SongLyrics{}, err
+	}
+	var versions []core.SongLyrics
+	if err := db.Model(&core.SongLyrics{}).
+		Select(versionColumns).
+		Joins("JOIN songs ON songs.id = song_lyrics.song_id").
+		Where("song_lyrics.song_id = ?", id).
+		Order("song_lyrics.original DESC, song_lyrics.lang").
+		Find(&versions).Error; err != nil {
+		return []core.SongLyrics{}, dberr.TranslateError(err, translationEntity)
+	}
+	if len(versions) == 0 || !versions[0].Original {
+		versions = append([]core.SongLyrics{{SongID: id, Original: true, Text: song.Text}}, versions...)
+	}
+	return versions, nil
+}
+
+func (s *store) GetSongLyrics(ctx context.Context, id int, lang string) (core.SongLyrics, error) {
+	return s.getSongLyrics(s.DB.WithContext(ctx), id, lang)
+}
+
+func (s *store) getSongLyrics(db *gorm.DB, id int, lang string) (core.SongLyrics, error) {
+	var version core.SongLyrics
+	err := db.Model(&core.SongLyrics{}).
+		Select(versionColumns).
+		Joins("JOIN songs ON songs.id = song_lyrics.song_id AND songs.deleted_at IS NULL").
+		Where("song_lyrics.song_id = ? AND song_lyrics.lang = ?", id, lang).
+		Take(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return core.SongLyrics{}, apperror.NotFound("song %d has no lyrics in %q", id, lang)
+	}
+	if err != nil {
+		return core.SongLyrics{}, dberr.TranslateError(err, translationEntity)
+	}
+	return version, nil
+}
@@ -0,0 +1,27 @@
+package user
+
+import "testing"
+
+func TestUnlabelledLanguage(t *testing.T) {
+	const english = "I love you and you love me, all the night in my heart"
+	tests := []struct {
+		name         string
+		text         string
+		taken        []string
+		wantLang     string
+		wantDetected bool
+	}{
+		{name: "detected", text: english, taken: []string{"ru"}, wantLang: "en", wantDetected: true},
+		{name: "detected language taken", text: english, taken: []string{"en"}, wantLang: "und"},
+		{name: "not detected", text: "la la", wantLang: "und"},
+		{name: "all taken", text: english, taken: []string{"en", "und"}, wantLang: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, detected := unlabelledLanguage(tt.text, tt.taken)
+			if lang != tt.wantLang || detected != tt.wantDetected {
+				t.Errorf("unlabelledLanguage() = %q, %v, want %q, %v", lang, detected, tt.wantLang, tt.wantDetected)
+			}
+		})
+	}
+}
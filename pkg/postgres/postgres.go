@@ -48,7 +48,8 @@ func New(ctx context.Context, dbURL string, opts ...Option) (*Postgres, error) {
 			NamingStrategy: schema.NamingStrategy{
 				SingularTable: true,
 			},
-			Logger: logger.Log(),
+			Logger:         logger.Log(),
+			TranslateError: true,
 		})
 		if err == nil {
 			sqlDB, err := db.DB()